@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/orijtech/seedco/v1"
+	"golang.org/x/term"
+)
+
+// commonFlags are the flags that every command accepts.
+type commonFlags struct {
+	baseURL   string
+	tokenFile string
+	format    string
+}
+
+func newFlagSet(env *cmdEnv, name string, withFormat bool) (*flag.FlagSet, *commonFlags) {
+	cf := new(commonFlags)
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(env.stderr)
+	fs.StringVar(&cf.baseURL, "base-url", os.Getenv(seedco.EnvBaseURLKey), `base URL of the API, or one of the presets "production" and "sandbox"`)
+	fs.StringVar(&cf.tokenFile, "token-file", defaultTokenFile(), "file that the token is stored in")
+	if withFormat {
+		fs.StringVar(&cf.format, "format", formatTable, "output format: table, json or csv")
+	}
+	return fs, cf
+}
+
+var errUnknownFormat = errors.New("unknown output format")
+
+func (cf *commonFlags) validate() error {
+	switch cf.format {
+	case "", formatTable, formatJSON, formatCSV:
+		return nil
+	default:
+		return fmt.Errorf("%w %q", errUnknownFormat, cf.format)
+	}
+}
+
+func (cf *commonFlags) configure(client *seedco.Client) error {
+	if cf.baseURL == "" {
+		return nil
+	}
+	return client.SetBaseURL(cf.baseURL)
+}
+
+// client returns a client authenticated with the stored token, or if
+// there is none, with the token in the environment. The returned save
+// function stores the token again if the client refreshed it.
+func (cf *commonFlags) client() (client *seedco.Client, save func() error, err error) {
+	tok, err := loadToken(cf.tokenFile)
+	switch {
+	case err == nil:
+		if client, err = seedco.NewClientFromToken(tok); err != nil {
+			return nil, nil, err
+		}
+	case os.IsNotExist(err):
+		if client, err = seedco.NewClientFromEnv(); err != nil {
+			return nil, nil, fmt.Errorf("%v; run \"seedco login\" first", err)
+		}
+	default:
+		return nil, nil, err
+	}
+	if err := cf.configure(client); err != nil {
+		return nil, nil, err
+	}
+
+	save = func() error {
+		cur := client.Token()
+		if tok == nil || cur == nil || cur.AccessToken == tok.AccessToken {
+			return nil
+		}
+		return saveToken(cf.tokenFile, cur)
+	}
+	return client, save, nil
+}
+
+// withClient runs fn with an authenticated client and
+// stores the token afterwards if it was refreshed.
+func (cf *commonFlags) withClient(fn func(*seedco.Client) error) error {
+	client, save, err := cf.client()
+	if err != nil {
+		return err
+	}
+	err = fn(client)
+	if serr := save(); err == nil {
+		err = serr
+	}
+	return err
+}
+
+var errBlankUsername = errors.New("-username is required")
+
+func runLogin(ctx context.Context, env *cmdEnv, args []string) error {
+	fs, cf := newFlagSet(env, "login", false)
+	username := fs.String("username", "", "username to log in as")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *username == "" {
+		return errBlankUsername
+	}
+	password := os.Getenv("SEEDCO_PASSWORD")
+	if password == "" {
+		var err error
+		if password, err = readPassword(env); err != nil {
+			return err
+		}
+	}
+
+	client, err := seedco.NewClient()
+	if err != nil {
+		return err
+	}
+	if err := cf.configure(client); err != nil {
+		return err
+	}
+	tok, err := client.AuthTokenWithContext(ctx, *username, password)
+	if err != nil {
+		return err
+	}
+	if err := saveToken(cf.tokenFile, tok); err != nil {
+		return err
+	}
+	fmt.Fprintf(env.stderr, "Token saved to %s\n", cf.tokenFile)
+	return nil
+}
+
+// readPassword prompts for the password and reads it from the first
+// line of stdin, without echoing it if stdin is a terminal.
+func readPassword(env *cmdEnv) (string, error) {
+	fmt.Fprint(env.stderr, "Password: ")
+	if f, ok := env.stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		blob, err := term.ReadPassword(int(f.Fd()))
+		fmt.Fprintln(env.stderr)
+		return string(blob), err
+	}
+	line, err := bufio.NewReader(env.stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func runRefresh(ctx context.Context, env *cmdEnv, args []string) error {
+	fs, cf := newFlagSet(env, "refresh", false)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	tok, err := loadToken(cf.tokenFile)
+	if err != nil {
+		return err
+	}
+	client, err := seedco.NewClient()
+	if err != nil {
+		return err
+	}
+	if err := cf.configure(client); err != nil {
+		return err
+	}
+	refreshed, err := client.RefreshTokenWithContext(ctx, tok.RefreshToken)
+	if err != nil {
+		return err
+	}
+	// The API may leave out the refresh token if it is unchanged.
+	if refreshed.RefreshToken == "" {
+		refreshed.RefreshToken = tok.RefreshToken
+	}
+	if err := saveToken(cf.tokenFile, refreshed); err != nil {
+		return err
+	}
+	fmt.Fprintf(env.stderr, "Token refreshed and saved to %s\n", cf.tokenFile)
+	return nil
+}
+
+func runBalances(ctx context.Context, env *cmdEnv, args []string) error {
+	fs, cf := newFlagSet(env, "balances", true)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := cf.validate(); err != nil {
+		return err
+	}
+	return cf.withClient(func(client *seedco.Client) error {
+		balances, err := client.ListBalancesWithContext(ctx)
+		if err != nil {
+			return err
+		}
+		return balancesOutput(balances).write(env.stdout, cf.format)
+	})
+}
+
+func runTransactions(ctx context.Context, env *cmdEnv, args []string) error {
+	fs, cf := newFlagSet(env, "transactions", true)
+	sp := new(seedco.SearchParams)
+	var status, start, end string
+	fs.StringVar(&sp.Query, "query", "", "only list transactions that match the query")
+	fs.StringVar(&status, "status", "", "only list pending or settled transactions")
+	fs.StringVar(&start, "start", "", "earliest date, as 2006-01-02 or RFC 3339")
+	fs.StringVar(&end, "end", "", "latest date, as 2006-01-02 or RFC 3339")
+	fs.IntVar(&sp.Offset, "offset", 0, "number of transactions to skip")
+	fs.IntVar(&sp.Limit, "limit", 0, "page size; 1000 if 0")
+	fs.Int64Var(&sp.MaxPageNumber, "max-pages", 0, "maximum number of pages to fetch; all if 0")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := cf.validate(); err != nil {
+		return err
+	}
+	var err error
+	if sp.Status, err = parseStatus(status); err != nil {
+		return err
+	}
+	if sp.StartDate, err = parseDate(start); err != nil {
+		return fmt.Errorf("-start: %v", err)
+	}
+	if sp.EndDate, err = parseDate(end); err != nil {
+		return fmt.Errorf("-end: %v", err)
+	}
+
+	return cf.withClient(func(client *seedco.Client) error {
+		var transactions []*seedco.Transaction
+		it := client.Transactions(sp)
+		for {
+			t, err := it.Next(ctx)
+			if err == seedco.Done {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			transactions = append(transactions, t)
+		}
+		return transactionsOutput(transactions).write(env.stdout, cf.format)
+	})
+}
+
+func runVersion(ctx context.Context, env *cmdEnv, args []string) error {
+	fs, cf := newFlagSet(env, "version", true)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := cf.validate(); err != nil {
+		return err
+	}
+	return cf.withClient(func(client *seedco.Client) error {
+		v, err := client.APIVersionWithContext(ctx)
+		if err != nil {
+			return err
+		}
+		return versionOutput(v).write(env.stdout, cf.format)
+	})
+}
+
+var errUnknownStatus = errors.New(`-status must be "pending" or "settled"`)
+
+func parseStatus(s string) (seedco.Status, error) {
+	switch status := seedco.Status(strings.ToLower(s)); status {
+	case "", seedco.Pending, seedco.Settled:
+		return status, nil
+	default:
+		return "", errUnknownStatus
+	}
+}
+
+func parseDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
@@ -0,0 +1,85 @@
+// Command seedco is a command line client of the Seed API.
+//
+// Usage:
+//
+//	seedco login -username <username>
+//	seedco refresh
+//	seedco balances [-format table|json|csv]
+//	seedco transactions [-query q] [-status pending|settled] [-start date] [-end date] [-offset n] [-limit n] [-max-pages n]
+//	seedco version
+//
+// login reads the password from the SEEDCO_PASSWORD environment
+// variable or else prompts for it on stdin. It stores the token it
+// obtains, by default in seedco/token.json under the user's
+// configuration directory, and the other commands use and refresh
+// that token. Without a stored token, they fall back to
+// the SEEDCO_BEARER_TOKEN environment variable.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	stop()
+	if err == flag.ErrHelp {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "seedco: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, env *cmdEnv, args []string) error
+}
+
+var commands = []*command{
+	{name: "login", summary: "obtain and store a token", run: runLogin},
+	{name: "refresh", summary: "refresh the stored token", run: runRefresh},
+	{name: "balances", summary: "list the balances of the checking accounts", run: runBalances},
+	{name: "transactions", summary: "search transactions", run: runTransactions},
+	{name: "version", summary: "print the API version", run: runVersion},
+}
+
+// cmdEnv holds the streams that a command reads from and writes to.
+type cmdEnv struct {
+	stdin          io.Reader
+	stdout, stderr io.Writer
+}
+
+var errUnknownCommand = errors.New("unknown command")
+
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	env := &cmdEnv{stdin: stdin, stdout: stdout, stderr: stderr}
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "-help" {
+		usage(stderr)
+		return flag.ErrHelp
+	}
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(ctx, env, args[1:])
+		}
+	}
+	usage(stderr)
+	return fmt.Errorf("%w %q", errUnknownCommand, args[0])
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: seedco <command> [flags]\n\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-14s%s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w, "\nRun \"seedco <command> -h\" for the flags of a command.")
+}
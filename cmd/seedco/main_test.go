@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orijtech/seedco/v1"
+	"github.com/orijtech/seedco/v1/seedcotest"
+)
+
+func newTestServer(t *testing.T) (*seedcotest.Server, []string) {
+	srv := seedcotest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddUser("ops", "hunter2")
+	srv.SetBalances(&seedco.Balance{CheckingAccountID: "a1", Accessible: 123456, TotalAvailable: 120000})
+	day := func(d int) *time.Time {
+		date := time.Date(2018, time.March, d, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+	srv.AddTransactions(
+		&seedco.Transaction{ID: "t1", Description: "Uber", Status: seedco.Settled, AmountCents: -1500, Date: day(1)},
+		&seedco.Transaction{ID: "t2", Description: "Lyft, SF", Status: seedco.Pending, AmountCents: -2250, Date: day(2)},
+		&seedco.Transaction{ID: "t3", Description: "Uber", Status: seedco.Pending, AmountCents: -990, Date: day(3)},
+	)
+	tokenFile := filepath.Join(t.TempDir(), "token.json")
+	return srv, []string{"-base-url", srv.URL, "-token-file", tokenFile}
+}
+
+func runCmd(t *testing.T, stdin string, args ...string) (string, error) {
+	t.Helper()
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	err := run(context.Background(), args, strings.NewReader(stdin), stdout, stderr)
+	return stdout.String(), err
+}
+
+func login(t *testing.T, common []string) {
+	t.Helper()
+	args := append([]string{"login", "-username", "ops"}, common...)
+	if _, err := runCmd(t, "hunter2\n", args...); err != nil {
+		t.Fatalf("login: unexpected error: %v", err)
+	}
+}
+
+func TestLoginAndRefresh(t *testing.T) {
+	srv, common := newTestServer(t)
+	tokenFile := common[3]
+
+	if _, err := runCmd(t, "wrong\n", append([]string{"login", "-username", "ops"}, common...)...); err == nil {
+		t.Errorf("wrong password: want non-nil error")
+	}
+	login(t, common)
+	tok, err := loadToken(tokenFile)
+	if err != nil {
+		t.Fatalf("stored token: %v", err)
+	}
+	if fi, err := os.Stat(tokenFile); err != nil || fi.Mode().Perm() != 0600 {
+		t.Errorf("token file: got mode %v (%v) want 0600", fi.Mode().Perm(), err)
+	}
+
+	if _, err := runCmd(t, "", append([]string{"refresh"}, common...)...); err != nil {
+		t.Fatalf("refresh: unexpected error: %v", err)
+	}
+	refreshed, err := loadToken(tokenFile)
+	if err != nil {
+		t.Fatalf("stored token: %v", err)
+	}
+	if refreshed.AccessToken == tok.AccessToken {
+		t.Errorf("refresh did not store a new token")
+	}
+
+	// An expired token is refreshed on use and stored again.
+	srv.ExpireToken(refreshed.AccessToken)
+	if _, err := runCmd(t, "", append([]string{"balances"}, common...)...); err != nil {
+		t.Fatalf("balances: unexpected error: %v", err)
+	}
+	if cur, _ := loadToken(tokenFile); cur == nil || cur.AccessToken == refreshed.AccessToken {
+		t.Errorf("the token refreshed on use was not stored")
+	}
+}
+
+func TestLoginPasswordFromEnv(t *testing.T) {
+	_, common := newTestServer(t)
+	args := append([]string{"login", "-username", "ops"}, common...)
+
+	if _, err := runCmd(t, "", append([]string{"login", "-username", "ops", "-password", "hunter2"}, common...)...); err == nil {
+		t.Errorf("-password: want non-nil error")
+	}
+	t.Setenv("SEEDCO_PASSWORD", "hunter2")
+	// The password is not read from stdin when the environment has it.
+	if _, err := runCmd(t, "wrong\n", args...); err != nil {
+		t.Errorf("SEEDCO_PASSWORD: unexpected error: %v", err)
+	}
+}
+
+func TestStoredTokenExpiry(t *testing.T) {
+	srv, common := newTestServer(t)
+	tokenFile := common[3]
+	login(t, common)
+
+	tok, err := loadToken(tokenFile)
+	if err != nil {
+		t.Fatalf("stored token: %v", err)
+	}
+	if tok.ExpiresIn <= 0 || tok.ExpiresIn > 3600 {
+		t.Errorf("expires in: got=%v want within the hour", tok.ExpiresIn)
+	}
+
+	// A token stored a day ago has expired, even though its
+	// relative expires_in is unchanged, and is refreshed
+	// before use rather than after being rejected.
+	blob, err := os.ReadFile(tokenFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := make(map[string]interface{})
+	if err := json.Unmarshal(blob, &stored); err != nil {
+		t.Fatal(err)
+	}
+	stored["expiry"] = time.Now().Add(-24 * time.Hour).Format(time.RFC3339)
+	if blob, err = json.Marshal(stored); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(tokenFile, blob, 0600); err != nil {
+		t.Fatal(err)
+	}
+	srv.ExpireToken(tok.AccessToken)
+	if _, err := runCmd(t, "", append([]string{"balances"}, common...)...); err != nil {
+		t.Fatalf("balances: unexpected error: %v", err)
+	}
+	if g, w := srv.Requests(seedcotest.BalanceRoute), 1; g != w {
+		t.Errorf("balance requests: got=%d want=%d", g, w)
+	}
+	if cur, _ := loadToken(tokenFile); cur == nil || cur.AccessToken == tok.AccessToken || cur.ExpiresIn < 60 {
+		t.Errorf("the refreshed token was not stored with its new expiry: %+v", cur)
+	}
+}
+
+func TestRefreshKeepsRefreshToken(t *testing.T) {
+	// The API may leave out the refresh token if it is unchanged.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, `{"results":[{"access_token":"access-2","expires_in":3600}]}`)
+	}))
+	defer srv.Close()
+	tokenFile := filepath.Join(t.TempDir(), "token.json")
+	if err := saveToken(tokenFile, &seedco.Token{AccessToken: "access-1", RefreshToken: "refresh-1", ExpiresIn: 3600}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := runCmd(t, "", "refresh", "-base-url", srv.URL, "-token-file", tokenFile); err != nil {
+		t.Fatalf("refresh: unexpected error: %v", err)
+	}
+	tok, err := loadToken(tokenFile)
+	if err != nil {
+		t.Fatalf("stored token: %v", err)
+	}
+	if tok.AccessToken != "access-2" || tok.RefreshToken != "refresh-1" {
+		t.Errorf("got=(%q, %q) want=(access-2, refresh-1)", tok.AccessToken, tok.RefreshToken)
+	}
+}
+
+func TestBalances(t *testing.T) {
+	_, common := newTestServer(t)
+	login(t, common)
+
+	tests := [...]struct {
+		format string
+		want   string
+	}{
+		0: {format: "table", want: "a1                   $1,200.00"},
+		1: {format: "csv", want: "a1,1200.00,1234.56,0.00,0.00,0.00,0.00,0.00\n"},
+		2: {format: "json", want: `"total_available": 120000`},
+	}
+
+	for i, tt := range tests {
+		out, err := runCmd(t, "", append([]string{"balances", "-format", tt.format}, common...)...)
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+			continue
+		}
+		if !strings.Contains(out, tt.want) {
+			t.Errorf("#%d: got=%q want it to contain %q", i, out, tt.want)
+		}
+	}
+
+	if _, err := runCmd(t, "", append([]string{"balances", "-format", "xml"}, common...)...); err == nil {
+		t.Errorf("unknown format: want non-nil error")
+	}
+}
+
+func TestTransactions(t *testing.T) {
+	_, common := newTestServer(t)
+	login(t, common)
+
+	tests := [...]struct {
+		args    []string
+		wantIDs []string
+		wantErr bool
+	}{
+		0: {wantIDs: []string{"t3", "t2", "t1"}},
+		1: {args: []string{"-query", "uber"}, wantIDs: []string{"t3", "t1"}},
+		2: {args: []string{"-status", "pending"}, wantIDs: []string{"t3", "t2"}},
+		3: {args: []string{"-start", "2018-03-02", "-end", "2018-03-02T23:59:59Z"}, wantIDs: []string{"t2"}},
+		4: {args: []string{"-limit", "1", "-max-pages", "2"}, wantIDs: []string{"t3", "t2"}},
+		5: {args: []string{"-offset", "2"}, wantIDs: []string{"t1"}},
+		6: {args: []string{"-status", "bounced"}, wantErr: true},
+		7: {args: []string{"-start", "yesterday"}, wantErr: true},
+	}
+
+	for i, tt := range tests {
+		args := append(append([]string{"transactions", "-format", "json"}, common...), tt.args...)
+		out, err := runCmd(t, "", args...)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: want non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+			continue
+		}
+		var transactions []*seedco.Transaction
+		if err := json.Unmarshal([]byte(out), &transactions); err != nil {
+			t.Errorf("#%d: %v", i, err)
+			continue
+		}
+		var ids []string
+		for _, txn := range transactions {
+			ids = append(ids, txn.ID)
+		}
+		if g, w := strings.Join(ids, ","), strings.Join(tt.wantIDs, ","); g != w {
+			t.Errorf("#%d: got=%q want=%q", i, g, w)
+		}
+	}
+
+	out, err := runCmd(t, "", append([]string{"transactions", "-format", "csv", "-query", "lyft"}, common...)...)
+	if err != nil {
+		t.Fatalf("csv: unexpected error: %v", err)
+	}
+	want := "id,date,status,amount,category,description,memo,checking_account_id\n" +
+		"t2,2018-03-02T00:00:00Z,pending,-22.50,,\"Lyft, SF\",,\n"
+	if out != want {
+		t.Errorf("csv: got=%q want=%q", out, want)
+	}
+}
+
+func TestVersion(t *testing.T) {
+	_, common := newTestServer(t)
+	login(t, common)
+
+	out, err := runCmd(t, "", append([]string{"version"}, common...)...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "seedcotest") {
+		t.Errorf("got=%q want the fake's version", out)
+	}
+
+	if _, err := runCmd(t, "", "frobnicate"); err == nil {
+		t.Errorf("unknown command: want non-nil error")
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/orijtech/seedco/v1"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+// output is the result of a command: rows for the table and CSV
+// formats, and the value itself for the JSON format. Table cells
+// are formatted for reading and CSV cells for importing.
+type output struct {
+	header    []string
+	tableRows [][]string
+	csvRows   [][]string
+	value     interface{}
+}
+
+func (o *output) write(w io.Writer, format string) error {
+	switch format {
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(o.value)
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(o.header); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(o.csvRows); err != nil {
+			return err
+		}
+		return cw.Error()
+	default:
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.ToUpper(strings.Join(o.header, "\t")))
+		for _, row := range o.tableRows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	}
+}
+
+func balancesOutput(balances []*seedco.Balance) *output {
+	o := &output{
+		header: []string{"checking_account_id", "total_available", "accessible", "settled", "pending_debits", "pending_credits", "scheduled_debits", "lockbox"},
+		value:  balances,
+	}
+	if balances == nil {
+		o.value = []*seedco.Balance{}
+	}
+	for _, b := range balances {
+		amounts := []seedco.Cents{b.TotalAvailable, b.Accessible, b.Settled, b.PendingDebits, b.PendingCredits, b.ScheduledDebits, b.Lockbox}
+		tableRow := []string{b.CheckingAccountID}
+		csvRow := []string{b.CheckingAccountID}
+		for _, amount := range amounts {
+			tableRow = append(tableRow, amount.String())
+			csvRow = append(csvRow, amount.Decimal())
+		}
+		o.tableRows = append(o.tableRows, tableRow)
+		o.csvRows = append(o.csvRows, csvRow)
+	}
+	return o
+}
+
+func transactionsOutput(transactions []*seedco.Transaction) *output {
+	o := &output{
+		header: []string{"id", "date", "status", "amount", "category", "description", "memo", "checking_account_id"},
+		value:  transactions,
+	}
+	if transactions == nil {
+		o.value = []*seedco.Transaction{}
+	}
+	for _, t := range transactions {
+		row := func(date, amount string) []string {
+			return []string{t.ID, date, string(t.Status), amount, string(t.Category), t.Description, t.Memo, t.CheckingAccountID}
+		}
+		o.tableRows = append(o.tableRows, row(formatDate(t.Date, "2006-01-02"), t.AmountCents.String()))
+		o.csvRows = append(o.csvRows, row(formatDate(t.Date, time.RFC3339), t.AmountCents.Decimal()))
+	}
+	return o
+}
+
+func versionOutput(v *seedco.APIVersion) *output {
+	row := []string{v.Version, v.ID, formatDate(v.UpdatedAt, time.RFC3339)}
+	return &output{
+		header:    []string{"api_version", "id", "updated_at"},
+		tableRows: [][]string{row},
+		csvRows:   [][]string{row},
+		value:     v,
+	}
+}
+
+func formatDate(t *time.Time, layout string) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(layout)
+}
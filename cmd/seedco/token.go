@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/orijtech/seedco/v1"
+)
+
+// envTokenFileKey optionally names the file that the token is stored in.
+const envTokenFileKey = "SEEDCO_TOKEN_FILE"
+
+func defaultTokenFile() string {
+	if path := os.Getenv(envTokenFileKey); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "seedco-token.json"
+	}
+	return filepath.Join(dir, "seedco", "token.json")
+}
+
+func loadToken(path string) (*seedco.Token, error) {
+	blob, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tok := new(seedco.Token)
+	if err := json.Unmarshal(blob, tok); err != nil {
+		return nil, err
+	}
+	if tok.Expiry.IsZero() {
+		// Without Expiry, there is nothing to measure ExpiresIn from.
+		tok.ExpiresIn = 0
+	}
+	return tok, nil
+}
+
+// saveToken writes tok to path, readable only by the current user,
+// replacing the previous token atomically. Unless tok.Expiry is set,
+// tok.ExpiresIn is measured from now.
+func saveToken(path string, tok *seedco.Token) error {
+	if tok.Expiry.IsZero() && tok.ExpiresIn != 0 {
+		cp := *tok
+		cp.Expiry = time.Now().Add(time.Duration(tok.ExpiresIn * float64(time.Second))).UTC()
+		tok = &cp
+	}
+	blob, err := json.MarshalIndent(tok, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(path), ".token-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(blob); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
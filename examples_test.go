@@ -1,6 +1,7 @@
 package seedco_test
 
 import (
+	"context"
 	"log"
 
 	"github.com/orijtech/seedco/v1"
@@ -61,7 +62,7 @@ func Example_client_ListTransactions() {
 			continue
 		}
 		for i, transaction := range page.Transactions {
-			log.Printf("PageNumber: %d Transaction #%d\n", page.PageNumber, i, transaction)
+			log.Printf("PageNumber: %d Transaction #%d: %#v\n", page.PageNumber, i, transaction)
 		}
 	}
 }
@@ -86,11 +87,33 @@ func Example_client_SearchTransactions() {
 			continue
 		}
 		for i, transaction := range page.Transactions {
-			log.Printf("PageNumber: %d Transaction #%d\n", page.PageNumber, i, transaction)
+			log.Printf("PageNumber: %d Transaction #%d: %#v\n", page.PageNumber, i, transaction)
 		}
 	}
 }
 
+func Example_client_Transactions() {
+	client, err := seedco.NewClientFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	ctx := context.Background()
+	it := client.Transactions(&seedco.SearchParams{
+		Query: "Chipotle",
+		Limit: 100,
+	})
+	for {
+		transaction, err := it.Next(ctx)
+		if err == seedco.Done {
+			break
+		}
+		if err != nil {
+			log.Fatalf("resume from offset %d: %v", it.Offset(), err)
+		}
+		log.Printf("Transaction: %#v\n", transaction)
+	}
+}
+
 func Example_client_ListBalances() {
 	client, err := seedco.NewClientFromEnv()
 	if err != nil {
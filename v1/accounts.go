@@ -0,0 +1,111 @@
+package seedco
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type AccountStatus string
+
+const (
+	AccountActive AccountStatus = "active"
+	AccountFrozen AccountStatus = "frozen"
+	AccountClosed AccountStatus = "closed"
+)
+
+type CheckingAccount struct {
+	ID string `json:"id,omitempty"`
+
+	// Nickname is the user chosen name of the account.
+	Nickname string `json:"nickname,omitempty"`
+
+	AccountNumber string `json:"account_number,omitempty"`
+	RoutingNumber string `json:"routing_number,omitempty"`
+
+	Status AccountStatus `json:"status,omitempty"`
+
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+type checkingAccountsResponse struct {
+	Accounts []*CheckingAccount `json:"results"`
+	Errors   []*Error           `json:"errors"`
+}
+
+var (
+	errBlankCheckingAccountID = errors.New("checking account ID must be non-blank")
+	errNoCheckingAccount      = errors.New("no checking account received")
+)
+
+// ListCheckingAccounts returns every checking account that the token can access.
+func (c *Client) ListCheckingAccounts(ctx context.Context) ([]*CheckingAccount, error) {
+	fullURL := fmt.Sprintf("%s/public/checking_accounts", c.BaseURL())
+	return c.doCheckingAccountsReq(ctx, fullURL)
+}
+
+// GetCheckingAccount returns the checking account with the given ID.
+func (c *Client) GetCheckingAccount(ctx context.Context, id string) (*CheckingAccount, error) {
+	if id == "" {
+		return nil, errBlankCheckingAccountID
+	}
+	fullURL := fmt.Sprintf("%s/public/checking_accounts/%s", c.BaseURL(), url.PathEscape(id))
+	accounts, err := c.doCheckingAccountsReq(ctx, fullURL)
+	if err != nil {
+		return nil, err
+	}
+	if len(accounts) == 0 || accounts[0] == nil {
+		return nil, errNoCheckingAccount
+	}
+	return accounts[0], nil
+}
+
+func (c *Client) doCheckingAccountsReq(ctx context.Context, fullURL string) ([]*CheckingAccount, error) {
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	blob, res, err := c.doAuthAndReq(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	car := new(checkingAccountsResponse)
+	if err := json.Unmarshal(blob, car); err != nil {
+		return nil, err
+	}
+	if err := flattenErrs(res, blob, car.Errors); err != nil {
+		return nil, err
+	}
+	return car.Accounts, nil
+}
+
+// CheckingAccountsByID lists the checking accounts keyed by their ID, which
+// is handy to resolve the CheckingAccountID of many balances or transactions
+// with a single request.
+func (c *Client) CheckingAccountsByID(ctx context.Context) (map[string]*CheckingAccount, error) {
+	accounts, err := c.ListCheckingAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*CheckingAccount, len(accounts))
+	for _, acct := range accounts {
+		if acct != nil {
+			byID[acct.ID] = acct
+		}
+	}
+	return byID, nil
+}
+
+// CheckingAccount fetches the checking account that the balance belongs to.
+func (b *Balance) CheckingAccount(ctx context.Context, c *Client) (*CheckingAccount, error) {
+	return c.GetCheckingAccount(ctx, b.CheckingAccountID)
+}
+
+// CheckingAccount fetches the checking account that the transaction belongs to.
+func (t *Transaction) CheckingAccount(ctx context.Context, c *Client) (*CheckingAccount, error) {
+	return c.GetCheckingAccount(ctx, t.CheckingAccountID)
+}
@@ -0,0 +1,67 @@
+package seedco_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/orijtech/seedco/v1"
+	"github.com/orijtech/seedco/v1/seedcotest"
+)
+
+func TestCheckingAccounts(t *testing.T) {
+	srv := seedcotest.NewUnstartedServer()
+	srv.AddCheckingAccounts(
+		&seedco.CheckingAccount{ID: "acct-1", Nickname: "Operating", AccountNumber: "000123", RoutingNumber: "021000021", Status: seedco.AccountActive},
+		&seedco.CheckingAccount{ID: "acct-2", Nickname: "Payroll", Status: seedco.AccountFrozen},
+	)
+	srv.SetBalances(&seedco.Balance{CheckingAccountID: "acct-2", Accessible: 100})
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	accounts, err := client.ListCheckingAccounts(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g, w := len(accounts), 2; g != w {
+		t.Fatalf("accounts: got=%d want=%d", g, w)
+	}
+
+	acct, err := client.GetCheckingAccount(ctx, "acct-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g, w := acct.RoutingNumber, "021000021"; g != w {
+		t.Errorf("routingNumber: got=%q want=%q", g, w)
+	}
+
+	if _, err := client.GetCheckingAccount(ctx, "acct-3"); !errors.Is(err, seedco.ErrNotFound) {
+		t.Errorf("unknown account: got=(%v) want=(%v)", err, seedco.ErrNotFound)
+	}
+	if _, err := client.GetCheckingAccount(ctx, ""); err == nil {
+		t.Errorf("blank ID: want non-nil error")
+	}
+
+	balances, err := client.ListBalancesWithContext(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	acct, err = balances[0].CheckingAccount(ctx, client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g, w := acct.Nickname, "Payroll"; g != w {
+		t.Errorf("balance's account: got=%q want=%q", g, w)
+	}
+
+	byID, err := client.CheckingAccountsByID(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g, w := byID["acct-2"].Status, seedco.AccountFrozen; g != w {
+		t.Errorf("status: got=%q want=%q", g, w)
+	}
+}
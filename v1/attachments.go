@@ -0,0 +1,164 @@
+package seedco
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Attachment is a file, such as a receipt, attached to a transaction.
+type Attachment struct {
+	ID string `json:"id,omitempty"`
+
+	TransactionID string `json:"transaction_id,omitempty"`
+
+	Filename    string `json:"filename,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+
+	// Size is the size of the file in bytes.
+	Size int64 `json:"size,omitempty"`
+
+	// URL is where the API serves the file from. Use
+	// DownloadAttachment to fetch it with authentication.
+	URL string `json:"url,omitempty"`
+
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+type attachmentsResponse struct {
+	Attachments []*Attachment `json:"results"`
+	Errors      []*Error      `json:"errors"`
+}
+
+var (
+	errBlankAttachmentID = errors.New("attachment ID must be non-blank")
+	errBlankFilename     = errors.New("filename must be non-blank")
+	errNilReader         = errors.New("expecting a non-nil io.Reader")
+	errNilWriter         = errors.New("expecting a non-nil io.Writer")
+	errNoAttachment      = errors.New("no attachment received")
+)
+
+func (c *Client) attachmentsURL(transactionID string) string {
+	return fmt.Sprintf("%s/public/transactions/%s/attachments", c.BaseURL(), url.PathEscape(transactionID))
+}
+
+// ListAttachments returns the attachments of the transaction with the given ID.
+func (c *Client) ListAttachments(ctx context.Context, transactionID string) ([]*Attachment, error) {
+	if transactionID == "" {
+		return nil, errBlankTransactionID
+	}
+	req, err := http.NewRequest("GET", c.attachmentsURL(transactionID), nil)
+	if err != nil {
+		return nil, err
+	}
+	blob, res, err := c.doAuthAndReq(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return parseAttachments(blob, res)
+}
+
+func parseAttachments(blob []byte, res *http.Response) ([]*Attachment, error) {
+	ar := new(attachmentsResponse)
+	if err := json.Unmarshal(blob, ar); err != nil {
+		return nil, err
+	}
+	if err := flattenErrs(res, blob, ar.Errors); err != nil {
+		return nil, err
+	}
+	return ar.Attachments, nil
+}
+
+// DownloadAttachment streams the content of an attachment to w
+// and returns the number of bytes written.
+func (c *Client) DownloadAttachment(ctx context.Context, transactionID, attachmentID string, w io.Writer) (int64, error) {
+	if transactionID == "" {
+		return 0, errBlankTransactionID
+	}
+	if attachmentID == "" {
+		return 0, errBlankAttachmentID
+	}
+	if w == nil {
+		return 0, errNilWriter
+	}
+	fullURL := fmt.Sprintf("%s/%s/content", c.attachmentsURL(transactionID), url.PathEscape(attachmentID))
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	res, err := c.doAuthAndStream(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	return io.Copy(w, res.Body)
+}
+
+// UploadAttachment attaches the content read from r to the transaction
+// with the given ID. The content is streamed as a multipart/form-data
+// request rather than buffered, so r is read exactly once.
+func (c *Client) UploadAttachment(ctx context.Context, transactionID, filename, contentType string, r io.Reader) (*Attachment, error) {
+	if transactionID == "" {
+		return nil, errBlankTransactionID
+	}
+	if filename == "" {
+		return nil, errBlankFilename
+	}
+	if r == nil {
+		return nil, errNilReader
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	prc, pwc := io.Pipe()
+	mw := multipart.NewWriter(pwc)
+	go func() {
+		hdr := make(textproto.MIMEHeader)
+		hdr.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, escapeQuotes(filename)))
+		hdr.Set("Content-Type", contentType)
+		part, err := mw.CreatePart(hdr)
+		if err == nil {
+			_, err = io.Copy(part, r)
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		_ = pwc.CloseWithError(err)
+	}()
+
+	req, err := http.NewRequest("POST", c.attachmentsURL(transactionID), prc)
+	if err != nil {
+		_ = prc.Close()
+		return nil, err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	blob, res, err := c.doAuthAndReq(ctx, req)
+	// Unblock the writer goroutine if the request ended early.
+	_ = prc.Close()
+	if err != nil {
+		return nil, err
+	}
+	attachments, err := parseAttachments(blob, res)
+	if err != nil {
+		return nil, err
+	}
+	if len(attachments) == 0 || attachments[0] == nil {
+		return nil, errNoAttachment
+	}
+	return attachments[0], nil
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
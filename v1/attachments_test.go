@@ -0,0 +1,82 @@
+package seedco_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/orijtech/seedco/v1"
+	"github.com/orijtech/seedco/v1/seedcotest"
+)
+
+func TestAttachments(t *testing.T) {
+	srv := seedcotest.NewServer()
+	defer srv.Close()
+	srv.AddTransactions(&seedco.Transaction{ID: "t1", Description: "Chipotle"})
+	existing := srv.AddAttachment("t1", "receipt.txt", "text/plain", []byte("burrito $7.36"))
+
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	content := strings.Repeat("%PDF-1.4 receipt ", 1000)
+	uploaded, err := client.UploadAttachment(ctx, "t1", `scan "1".pdf`, "application/pdf", strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("upload: unexpected error: %v", err)
+	}
+	if g, w := uploaded.Filename, `scan "1".pdf`; g != w {
+		t.Errorf("filename: got=%q want=%q", g, w)
+	}
+	if g, w := uploaded.ContentType, "application/pdf"; g != w {
+		t.Errorf("contentType: got=%q want=%q", g, w)
+	}
+	if g, w := uploaded.Size, int64(len(content)); g != w {
+		t.Errorf("size: got=%d want=%d", g, w)
+	}
+
+	attachments, err := client.ListAttachments(ctx, "t1")
+	if err != nil {
+		t.Fatalf("list: unexpected error: %v", err)
+	}
+	if g, w := len(attachments), 2; g != w {
+		t.Fatalf("attachments: got=%d want=%d", g, w)
+	}
+
+	// Attachments are decoded as part of the transaction too.
+	txn, err := client.GetTransaction(ctx, "t1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g, w := len(txn.Attachments), 2; g != w {
+		t.Errorf("transaction attachments: got=%d want=%d", g, w)
+	} else if g, w := txn.Attachments[0].Filename, existing.Filename; g != w {
+		t.Errorf("transaction attachment filename: got=%q want=%q", g, w)
+	}
+
+	for _, att := range []*seedco.Attachment{existing, uploaded} {
+		want, _ := srv.AttachmentContent("t1", att.ID)
+		buf := new(bytes.Buffer)
+		n, err := client.DownloadAttachment(ctx, "t1", att.ID, buf)
+		if err != nil {
+			t.Errorf("%s: download: unexpected error: %v", att.Filename, err)
+			continue
+		}
+		if g, w := n, int64(len(want)); g != w {
+			t.Errorf("%s: bytes written: got=%d want=%d", att.Filename, g, w)
+		}
+		if !bytes.Equal(buf.Bytes(), want) {
+			t.Errorf("%s: content mismatch", att.Filename)
+		}
+	}
+
+	if _, err := client.DownloadAttachment(ctx, "t1", "missing", new(bytes.Buffer)); !errors.Is(err, seedco.ErrNotFound) {
+		t.Errorf("missing attachment: got=(%v) want=(%v)", err, seedco.ErrNotFound)
+	}
+	if _, err := client.UploadAttachment(ctx, "t2", "a.txt", "", strings.NewReader("x")); !errors.Is(err, seedco.ErrNotFound) {
+		t.Errorf("unknown transaction: got=(%v) want=(%v)", err, seedco.ErrNotFound)
+	}
+}
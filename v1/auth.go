@@ -2,18 +2,31 @@ package seedco
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 )
 
 type Token struct {
-	AccessToken  string  `json:"access_token"`
-	ExpiresIn    float64 `json:"expires_in"`
-	Permissions  string  `json:"permissions"`
-	RefreshToken string  `json:"refresh_token"`
-	TokenType    string  `json:"token_type"`
+	AccessToken string `json:"access_token"`
+
+	// ExpiresIn is the number of seconds the token is valid for.
+	// Zero means that it never expires and a negative value that
+	// it already expired.
+	ExpiresIn float64 `json:"expires_in"`
+
+	Permissions  string `json:"permissions"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+
+	// Expiry, if set, is when the token lapses and takes precedence
+	// over ExpiresIn. The tokens returned by Client.Token and
+	// TokenFromOAuth2 carry it, so that they keep their expiry
+	// when stored and used later.
+	Expiry time.Time `json:"expiry,omitempty"`
 }
 
 type tokenListing struct {
@@ -29,23 +42,29 @@ var (
 )
 
 func (c *Client) AuthToken(username, password string) (*Token, error) {
+	return c.AuthTokenWithContext(context.Background(), username, password)
+}
+
+// AuthTokenWithContext is like AuthToken but
+// the request is bound to the lifetime of ctx.
+func (c *Client) AuthTokenWithContext(ctx context.Context, username, password string) (*Token, error) {
 	if username == "" {
 		return nil, errBlankUsername
 	}
 	if password == "" {
 		return nil, errBlankPassword
 	}
-	fullURL := fmt.Sprintf("%s/public/auth/token", baseURL)
+	fullURL := fmt.Sprintf("%s/public/auth/token", c.BaseURL())
 	req, err := http.NewRequest("POST", fullURL, nil)
 	if err != nil {
 		return nil, err
 	}
 	req.SetBasicAuth(username, password)
-	return c.doReqAndParseOutTokenFromTokenListing(req)
+	return c.doReqAndParseOutTokenFromTokenListing(ctx, req)
 }
 
-func (c *Client) doReqAndParseOutTokenFromTokenListing(req *http.Request) (*Token, error) {
-	blob, _, err := c.doReq(req)
+func (c *Client) doReqAndParseOutTokenFromTokenListing(ctx context.Context, req *http.Request) (*Token, error) {
+	blob, res, err := c.doReq(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -53,7 +72,7 @@ func (c *Client) doReqAndParseOutTokenFromTokenListing(req *http.Request) (*Toke
 	if err := json.Unmarshal(blob, tkl); err != nil {
 		return nil, err
 	}
-	if err := flattenErrs(tkl.Errors); err != nil {
+	if err := flattenErrs(res, blob, tkl.Errors); err != nil {
 		return nil, err
 	}
 	var token *Token
@@ -67,6 +86,12 @@ func (c *Client) doReqAndParseOutTokenFromTokenListing(req *http.Request) (*Toke
 }
 
 func (c *Client) RefreshToken(refreshToken string) (*Token, error) {
+	return c.RefreshTokenWithContext(context.Background(), refreshToken)
+}
+
+// RefreshTokenWithContext is like RefreshToken but
+// the request is bound to the lifetime of ctx.
+func (c *Client) RefreshTokenWithContext(ctx context.Context, refreshToken string) (*Token, error) {
 	if refreshToken == "" {
 		return nil, errRefreshToken
 	}
@@ -74,11 +99,11 @@ func (c *Client) RefreshToken(refreshToken string) (*Token, error) {
 	if err != nil {
 		return nil, err
 	}
-	fullURL := fmt.Sprintf("%s/public/auth/token/refresh", refreshToken)
+	fullURL := fmt.Sprintf("%s/public/auth/token/refresh", c.BaseURL())
 	req, err := http.NewRequest("POST", fullURL, bytes.NewReader(blob))
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("Content-Type", "application/json")
-	return c.doReqAndParseOutTokenFromTokenListing(req)
+	return c.doReqAndParseOutTokenFromTokenListing(ctx, req)
 }
@@ -9,7 +9,9 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/orijtech/seedco/v1"
 )
@@ -100,6 +102,174 @@ func TestRefreshToken(t *testing.T) {
 	}
 }
 
+func TestTokenRefreshedOnUnauthorized(t *testing.T) {
+	lb := &tokenLifecycleBackend{accessToken: "access-2", refreshToken: "refresh-1"}
+	client, err := seedco.NewClientFromToken(&seedco.Token{
+		AccessToken:  "access-1",
+		RefreshToken: "refresh-1",
+		ExpiresIn:    3600,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetHTTPRoundTripper(lb)
+
+	balances, err := client.ListBalances()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(balances) == 0 {
+		t.Errorf("expected balances")
+	}
+	if g, w := lb.refreshCount(), 1; g != w {
+		t.Errorf("refreshes: got=%d want=%d", g, w)
+	}
+	if g, w := client.Token().AccessToken, "access-2"; g != w {
+		t.Errorf("accessToken: got=%q want=%q", g, w)
+	}
+}
+
+func TestTokenRefreshedBeforeExpiry(t *testing.T) {
+	lb := &tokenLifecycleBackend{accessToken: "access-1", refreshToken: "refresh-1"}
+	client, err := seedco.NewClientFromToken(&seedco.Token{
+		AccessToken:  "access-1",
+		RefreshToken: "refresh-1",
+		ExpiresIn:    1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetHTTPRoundTripper(lb)
+
+	if _, err := client.ListBalances(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g, w := lb.refreshCount(), 1; g != w {
+		t.Errorf("refreshes: got=%d want=%d", g, w)
+	}
+	if g, w := lb.unauthorizedCount(), 0; g != w {
+		t.Errorf("unauthorized responses: got=%d want=%d", g, w)
+	}
+}
+
+func TestTokenConcurrentRefreshesAreSerialized(t *testing.T) {
+	lb := &tokenLifecycleBackend{
+		accessToken:  "access-2",
+		refreshToken: "refresh-1",
+		refreshDelay: 50 * time.Millisecond,
+	}
+	client, err := seedco.NewClientFromToken(&seedco.Token{
+		AccessToken:  "access-1",
+		RefreshToken: "refresh-1",
+		ExpiresIn:    3600,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetHTTPRoundTripper(lb)
+
+	var wg sync.WaitGroup
+	errsChan := make(chan error, 10)
+	for i := 0; i < cap(errsChan); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.ListBalances()
+			errsChan <- err
+		}()
+	}
+	wg.Wait()
+	close(errsChan)
+
+	for err := range errsChan {
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if g, w := lb.refreshCount(), 1; g != w {
+		t.Errorf("refreshes: got=%d want=%d", g, w)
+	}
+}
+
+func TestSetAuthTokenDisablesRefresh(t *testing.T) {
+	lb := &tokenLifecycleBackend{accessToken: "access-2", refreshToken: "refresh-1"}
+	client, err := seedco.NewClientFromToken(&seedco.Token{AccessToken: "access-1", RefreshToken: "refresh-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetHTTPRoundTripper(lb)
+	client.SetAuthToken("access-1")
+
+	if _, err := client.ListBalances(); err == nil {
+		t.Fatal("want non-nil error")
+	}
+	if g, w := lb.refreshCount(), 0; g != w {
+		t.Errorf("refreshes: got=%d want=%d", g, w)
+	}
+	if tok := client.Token(); tok != nil {
+		t.Errorf("want nil token, got %+v", tok)
+	}
+}
+
+// tokenLifecycleBackend only accepts accessToken and
+// hands out "access-2" for refreshes with refreshToken.
+type tokenLifecycleBackend struct {
+	mu sync.Mutex
+
+	accessToken  string
+	refreshToken string
+	refreshDelay time.Duration
+
+	refreshes     int
+	unauthorizeds int
+}
+
+var _ http.RoundTripper = (*tokenLifecycleBackend)(nil)
+
+func (lb *tokenLifecycleBackend) refreshCount() int {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	return lb.refreshes
+}
+
+func (lb *tokenLifecycleBackend) unauthorizedCount() int {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	return lb.unauthorizeds
+}
+
+func (lb *tokenLifecycleBackend) RoundTrip(req *http.Request) (*http.Response, error) {
+	if strings.HasSuffix(req.URL.Path, "/auth/token/refresh") {
+		blob, _ := ioutil.ReadAll(req.Body)
+		recv := make(map[string]string)
+		if err := json.Unmarshal(blob, &recv); err != nil {
+			return makeResp(err.Error(), http.StatusBadRequest, nil)
+		}
+		time.Sleep(lb.refreshDelay)
+		lb.mu.Lock()
+		defer lb.mu.Unlock()
+		if recv["refresh_token"] != lb.refreshToken {
+			return makeResp("401 Unauthorized", http.StatusUnauthorized, nil)
+		}
+		lb.refreshes += 1
+		lb.accessToken = "access-2"
+		body := fmt.Sprintf(`{"results":[{"access_token":%q,"expires_in":3600}]}`, lb.accessToken)
+		return makeResp("200 OK", http.StatusOK, ioutil.NopCloser(strings.NewReader(body)))
+	}
+
+	token, badRes, err := ensureBearerTokenAuthd(req)
+	if badRes != nil || err != nil {
+		return badRes, err
+	}
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	if token != lb.accessToken {
+		lb.unauthorizeds += 1
+		return makeResp("401 Unauthorized", http.StatusUnauthorized, nil)
+	}
+	return respFromFile("./testdata/bank-acct1.json")
+}
+
 const (
 	testToken1 = "test-token-1"
 
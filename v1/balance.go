@@ -1,6 +1,7 @@
 package seedco
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -13,29 +14,30 @@ type Balance struct {
 	// PendingDebits settle. That is why
 	// TotalAvailable is the balance that is
 	// safe to spend.
-	Accessible float64 `json:"accessible,omitempty"`
+	Accessible Cents `json:"accessible,omitempty"`
 
 	// PendingDebits indicates the total value of
 	// debit transactions that haven't yet settled.
-	PendingDebits float64 `json:"pending_debits,omitempty"`
+	PendingDebits Cents `json:"pending_debits,omitempty"`
 
 	// PendingCredits indicates the total value of
 	// credit transactions that haven't yet settled.
-	PendingCredits float64 `json:"pending_credits,omitempty"`
+	PendingCredits Cents `json:"pending_credits,omitempty"`
 
 	// ScheduledDebits indicates the total
-	// balanced of scheduled debits.
-	ScheduledDebits float64 `json:"scheduled_debits,omitempty"`
+	// balanced of scheduled debits, that is
+	// of the payments that ListScheduledPayments lists.
+	ScheduledDebits Cents `json:"scheduled_debits,omitempty"`
 
 	// Settled is the total balance of settled transactions.
-	Settled float64 `json:"settled,omitempty"`
+	Settled Cents `json:"settled,omitempty"`
 
 	// Lockbox is the total balance  in the lockbox.
-	Lockbox float64 `json:"lockbox,omitempty"`
+	Lockbox Cents `json:"lockbox,omitempty"`
 
 	// TotalAvailable is the total balance that is safe to spend:
 	// TotalAvailable = Accessible - PendingDebits - ScheduledDebits.
-	TotalAvailable float64 `json:"total_available,omitempty"`
+	TotalAvailable Cents `json:"total_available,omitempty"`
 
 	CheckingAccountID string `json:"checking_account_id,omitempty"`
 }
@@ -46,12 +48,18 @@ type listBalancesResponse struct {
 }
 
 func (c *Client) ListBalances() ([]*Balance, error) {
-	fullURL := fmt.Sprintf("%s/public/balance", baseURL)
+	return c.ListBalancesWithContext(context.Background())
+}
+
+// ListBalancesWithContext is like ListBalances but
+// the request is bound to the lifetime of ctx.
+func (c *Client) ListBalancesWithContext(ctx context.Context) ([]*Balance, error) {
+	fullURL := fmt.Sprintf("%s/public/balance", c.BaseURL())
 	req, err := http.NewRequest("GET", fullURL, nil)
 	if err != nil {
 		return nil, err
 	}
-	blob, _, err := c.doAuthAndReq(req)
+	blob, res, err := c.doAuthAndReq(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -59,8 +67,9 @@ func (c *Client) ListBalances() ([]*Balance, error) {
 	if err := json.Unmarshal(blob, lbr); err != nil {
 		return nil, err
 	}
-	if err := flattenErrs(lbr.Errors); err != nil {
+	if err := flattenErrs(res, blob, lbr.Errors); err != nil {
 		return nil, err
 	}
+	c.checkBalances(lbr.Balances)
 	return lbr.Balances, nil
 }
@@ -1,8 +1,10 @@
 package seedco_test
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/orijtech/seedco/v1"
@@ -44,6 +46,24 @@ func TestListBalances(t *testing.T) {
 	}
 }
 
+func TestListBalancesWithContextCanceled(t *testing.T) {
+	client, err := seedco.NewClientWithToken(token1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: listBalancesRoute})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	balances, err := client.ListBalancesWithContext(ctx)
+	if err == nil {
+		t.Fatalf("want non-nil error, got balances: %+v", balances)
+	}
+	if !strings.Contains(err.Error(), context.Canceled.Error()) {
+		t.Errorf("got=(%v) want match=(%v)", err, context.Canceled)
+	}
+}
+
 const (
 	token1   = "token-1"
 	token2   = "token-2"
@@ -68,3 +88,98 @@ func listBalancesRoundTrip(req *http.Request) (*http.Response, error) {
 	fullPath := fmt.Sprintf("./testdata/%s.json", basename)
 	return respFromFile(fullPath)
 }
+
+func TestBalanceValidate(t *testing.T) {
+	tests := [...]struct {
+		balance *seedco.Balance
+		want    []string
+	}{
+		0: {balance: &seedco.Balance{Accessible: 1000, PendingDebits: 200, ScheduledDebits: 300, TotalAvailable: 500}},
+		1: {balance: &seedco.Balance{}},
+		2: {
+			balance: &seedco.Balance{Accessible: 1000, PendingDebits: 200, TotalAvailable: 1000},
+			want:    []string{"total_available=1000 want 800"},
+		},
+		3: {
+			balance: &seedco.Balance{Accessible: 1000, PendingDebits: -200, Lockbox: -1, TotalAvailable: 1200},
+			want:    []string{"pending_debits=-200 want 0", "lockbox=-1 want 0"},
+		},
+	}
+
+	for i, tt := range tests {
+		err := tt.balance.Validate()
+		if len(tt.want) == 0 {
+			if err != nil {
+				t.Errorf("#%d: unexpected error: %v", i, err)
+			}
+			continue
+		}
+		bws, ok := err.(seedco.BalanceWarnings)
+		if !ok {
+			t.Errorf("#%d: got=(%v) want BalanceWarnings", i, err)
+			continue
+		}
+		var got []string
+		for _, bw := range bws {
+			got = append(got, fmt.Sprintf("%s=%d want %d", bw.Field, bw.Got, bw.Want))
+		}
+		if g, w := strings.Join(got, "; "), strings.Join(tt.want, "; "); g != w {
+			t.Errorf("#%d: got=%q want=%q", i, g, w)
+		}
+	}
+}
+
+func TestBalanceWarningHandler(t *testing.T) {
+	client, err := seedco.NewClientWithToken(token1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: listBalancesRoute})
+
+	var warnings []*seedco.BalanceWarning
+	client.SetBalanceWarningHandler(func(bw *seedco.BalanceWarning) {
+		warnings = append(warnings, bw)
+	})
+	balances, err := client.ListBalances()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g, w := len(balances), 2; g != w {
+		t.Errorf("balances: got=%d want=%d", g, w)
+	}
+
+	// Neither record of bank-acct1.json adds up.
+	want := []*seedco.BalanceWarning{
+		{
+			CheckingAccountID: "dc7839b9-a609-47df-b883-3b8450a7a57e",
+			Invariant:         seedco.InvariantTotalAvailable,
+			Field:             "total_available",
+			Got:               31932,
+			Want:              26040,
+		},
+		{
+			CheckingAccountID: "891cc208-ee36-4ac1-b08c-178a94c964a2",
+			Invariant:         seedco.InvariantTotalAvailable,
+			Field:             "total_available",
+			Got:               84999,
+			Want:              -82672,
+		},
+	}
+	if g, w := len(warnings), len(want); g != w {
+		t.Fatalf("warnings: got=%d want=%d", g, w)
+	}
+	for i := range want {
+		if *warnings[i] != *want[i] {
+			t.Errorf("#%d: got=%+v want=%+v", i, warnings[i], want[i])
+		}
+	}
+
+	client.SetBalanceWarningHandler(nil)
+	warnings = nil
+	if _, err := client.ListBalances(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("disabled checks: got %d warnings", len(warnings))
+	}
+}
@@ -0,0 +1,125 @@
+package seedco
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BalanceInvariant names a relation that the fields of
+// a Balance are documented to satisfy.
+type BalanceInvariant string
+
+const (
+	// InvariantTotalAvailable is the documented definition of TotalAvailable.
+	InvariantTotalAvailable BalanceInvariant = "total_available = accessible - pending_debits - scheduled_debits"
+
+	// InvariantNonNegative holds for the totals that are sums of
+	// amounts, PendingDebits, PendingCredits, ScheduledDebits and Lockbox.
+	InvariantNonNegative BalanceInvariant = "totals are non-negative"
+)
+
+// BalanceWarning reports a Balance field that violates an invariant.
+type BalanceWarning struct {
+	CheckingAccountID string
+	Invariant         BalanceInvariant
+
+	// Field is the JSON name of the field that has
+	// the value Got instead of the expected Want.
+	Field string
+	Got   Cents
+	Want  Cents
+}
+
+func (bw *BalanceWarning) String() string {
+	return fmt.Sprintf("balance of %q: %s: got %s=%s, want %s", bw.CheckingAccountID, bw.Invariant, bw.Field, bw.Got, bw.Want)
+}
+
+// BalanceWarnings is the error that Balance.Validate returns.
+type BalanceWarnings []*BalanceWarning
+
+var _ error = BalanceWarnings(nil)
+
+func (bws BalanceWarnings) Error() string {
+	msgs := make([]string, 0, len(bws))
+	for _, bw := range bws {
+		msgs = append(msgs, bw.String())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Validate checks the documented invariants of b and returns
+// the violations as BalanceWarnings, or nil if there are none.
+func (b *Balance) Validate() error {
+	var bws BalanceWarnings
+	warn := func(inv BalanceInvariant, field string, got, want Cents) {
+		bws = append(bws, &BalanceWarning{
+			CheckingAccountID: b.CheckingAccountID,
+			Invariant:         inv,
+			Field:             field,
+			Got:               got,
+			Want:              want,
+		})
+	}
+
+	totals := []struct {
+		field string
+		value Cents
+	}{
+		{"pending_debits", b.PendingDebits},
+		{"pending_credits", b.PendingCredits},
+		{"scheduled_debits", b.ScheduledDebits},
+		{"lockbox", b.Lockbox},
+	}
+	for _, total := range totals {
+		if total.value < 0 {
+			warn(InvariantNonNegative, total.field, total.value, 0)
+		}
+	}
+
+	want, err := b.Accessible.Sub(b.PendingDebits)
+	if err == nil {
+		want, err = want.Sub(b.ScheduledDebits)
+	}
+	// An overflow is a violation in its own right, reported
+	// without a meaningful Want.
+	if err != nil || want != b.TotalAvailable {
+		warn(InvariantTotalAvailable, "total_available", b.TotalAvailable, want)
+	}
+
+	if len(bws) == 0 {
+		return nil
+	}
+	return bws
+}
+
+// SetBalanceWarningHandler makes ListBalances check every balance that
+// it receives with Balance.Validate and pass each violation to fn. The
+// balances are returned all the same. A nil fn disables the checks.
+func (c *Client) SetBalanceWarningHandler(fn func(*BalanceWarning)) {
+	c.mu.Lock()
+	c.onBalanceWarning = fn
+	c.mu.Unlock()
+}
+
+func (c *Client) balanceWarningHandler() func(*BalanceWarning) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.onBalanceWarning
+}
+
+func (c *Client) checkBalances(balances []*Balance) {
+	fn := c.balanceWarningHandler()
+	if fn == nil {
+		return
+	}
+	for _, b := range balances {
+		if b == nil {
+			continue
+		}
+		if bws, ok := b.Validate().(BalanceWarnings); ok {
+			for _, bw := range bws {
+				fn(bw)
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package seedco
+
+import "strings"
+
+// Category is the bookkeeping category of a transaction. The API
+// may report categories that are not among the constants below;
+// those are kept as is and reported as unknown by Known.
+type Category string
+
+const (
+	CategoryAdvertising          Category = "Advertising"
+	CategoryBankFees             Category = "Bank Fees"
+	CategoryInsurance            Category = "Insurance"
+	CategoryMealsEntertainment   Category = "Meals & Entertainment"
+	CategoryOfficeSupplies       Category = "Office Supplies"
+	CategoryPayroll              Category = "Payroll"
+	CategoryProfessionalServices Category = "Professional Services"
+	CategoryRent                 Category = "Rent"
+	CategorySoftware             Category = "Software"
+	CategoryTaxes                Category = "Taxes"
+	CategoryTransfer             Category = "Transfer"
+	CategoryTravel               Category = "Travel"
+	CategoryUtilities            Category = "Utilities"
+	CategoryOther                Category = "Other"
+)
+
+var knownCategories = []Category{
+	CategoryAdvertising,
+	CategoryBankFees,
+	CategoryInsurance,
+	CategoryMealsEntertainment,
+	CategoryOfficeSupplies,
+	CategoryPayroll,
+	CategoryProfessionalServices,
+	CategoryRent,
+	CategorySoftware,
+	CategoryTaxes,
+	CategoryTransfer,
+	CategoryTravel,
+	CategoryUtilities,
+	CategoryOther,
+}
+
+// Categories returns the known categories.
+func Categories() []Category {
+	return append([]Category(nil), knownCategories...)
+}
+
+// Known reports whether c is exactly one of the category constants.
+func (c Category) Known() bool {
+	for _, known := range knownCategories {
+		if c == known {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseCategory matches s case-insensitively against the known
+// categories. Unknown values are returned unchanged with ok false,
+// so that they can still be used as a Category.
+func ParseCategory(s string) (c Category, ok bool) {
+	trimmed := strings.TrimSpace(s)
+	for _, known := range knownCategories {
+		if strings.EqualFold(trimmed, string(known)) {
+			return known, true
+		}
+	}
+	return Category(s), false
+}
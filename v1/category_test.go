@@ -0,0 +1,48 @@
+package seedco_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/orijtech/seedco/v1"
+)
+
+func TestParseCategory(t *testing.T) {
+	tests := [...]struct {
+		in     string
+		want   seedco.Category
+		wantOK bool
+	}{
+		0: {"Travel", seedco.CategoryTravel, true},
+		1: {" meals & entertainment ", seedco.CategoryMealsEntertainment, true},
+		2: {"Duvet", "Duvet", false},
+		3: {"", "", false},
+	}
+
+	for i, tt := range tests {
+		got, ok := seedco.ParseCategory(tt.in)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("#%d: got=(%q, %t) want=(%q, %t)", i, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestUnknownCategoryRoundTrips(t *testing.T) {
+	txn := new(seedco.Transaction)
+	if err := json.Unmarshal([]byte(`{"category":"Electric bill"}`), txn); err != nil {
+		t.Fatal(err)
+	}
+	if txn.Category.Known() {
+		t.Errorf("%q unexpectedly known", txn.Category)
+	}
+	blob, err := json.Marshal(txn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, w := string(blob), `{"category":"Electric bill"}`; g != w {
+		t.Errorf("got=%s want=%s", g, w)
+	}
+	if !seedco.CategoryMealsEntertainment.Known() {
+		t.Errorf("%q unexpectedly unknown", seedco.CategoryMealsEntertainment)
+	}
+}
@@ -0,0 +1,119 @@
+package seedco
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// MemoryCursorStore is a CursorStore that keeps
+// cursors in memory, mostly useful for tests.
+type MemoryCursorStore struct {
+	mu      sync.Mutex
+	cursors map[string][]byte
+}
+
+var _ CursorStore = (*MemoryCursorStore)(nil)
+
+func NewMemoryCursorStore() *MemoryCursorStore {
+	return &MemoryCursorStore{cursors: make(map[string][]byte)}
+}
+
+func (mcs *MemoryCursorStore) LoadCursor(ctx context.Context, key string) (*SyncCursor, error) {
+	mcs.mu.Lock()
+	blob := mcs.cursors[key]
+	mcs.mu.Unlock()
+	if blob == nil {
+		return nil, nil
+	}
+	cur := new(SyncCursor)
+	if err := json.Unmarshal(blob, cur); err != nil {
+		return nil, err
+	}
+	return cur, nil
+}
+
+func (mcs *MemoryCursorStore) SaveCursor(ctx context.Context, key string, cur *SyncCursor) error {
+	// Cursors are stored serialized so that callers
+	// cannot mutate them behind the store's back.
+	blob, err := json.Marshal(cur)
+	if err != nil {
+		return err
+	}
+	mcs.mu.Lock()
+	mcs.cursors[key] = blob
+	mcs.mu.Unlock()
+	return nil
+}
+
+// FileCursorStore is a CursorStore that keeps
+// each cursor as a JSON file inside Dir.
+type FileCursorStore struct {
+	Dir string
+}
+
+var _ CursorStore = (*FileCursorStore)(nil)
+
+var errBlankCursorDir = errors.New("FileCursorStore: Dir must be non-blank")
+
+func (fcs *FileCursorStore) path(key string) (string, error) {
+	if fcs.Dir == "" {
+		return "", errBlankCursorDir
+	}
+	return filepath.Join(fcs.Dir, url.PathEscape(key)+".json"), nil
+}
+
+func (fcs *FileCursorStore) LoadCursor(ctx context.Context, key string) (*SyncCursor, error) {
+	path, err := fcs.path(key)
+	if err != nil {
+		return nil, err
+	}
+	blob, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cur := new(SyncCursor)
+	if err := json.Unmarshal(blob, cur); err != nil {
+		return nil, err
+	}
+	return cur, nil
+}
+
+// SaveCursor writes the cursor to a temporary file first and then
+// renames it, so that a crash never leaves a truncated cursor behind.
+func (fcs *FileCursorStore) SaveCursor(ctx context.Context, key string, cur *SyncCursor) error {
+	path, err := fcs.path(key)
+	if err != nil {
+		return err
+	}
+	blob, err := json.Marshal(cur)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(fcs.Dir, 0755); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(fcs.Dir, ".cursor-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := f.Name()
+	if _, err := f.Write(blob); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
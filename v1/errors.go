@@ -0,0 +1,133 @@
+package seedco
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors that an *APIError matches with errors.Is
+// depending on the HTTP status code of the failed response.
+var (
+	ErrUnauthorized = errors.New("seedco: unauthorized")
+	ErrForbidden    = errors.New("seedco: forbidden")
+	ErrNotFound     = errors.New("seedco: not found")
+	ErrRateLimited  = errors.New("seedco: rate limited")
+	ErrConflict     = errors.New("seedco: conflicting update")
+	ErrServer       = errors.New("seedco: server error")
+)
+
+// APIError is returned for responses with a non-2XX status
+// and for 2XX responses whose body reports errors.
+type APIError struct {
+	// StatusCode and Status are those of the response, even
+	// a successful one whose body reported the errors.
+	StatusCode int
+	Status     string
+
+	// RequestID is the server assigned ID of the
+	// failed request, useful when contacting support.
+	RequestID string
+	Header    http.Header
+
+	// Body is the raw body of the failed response.
+	Body []byte
+
+	// Errors are the errors decoded from the response body.
+	Errors []*Error
+}
+
+var _ error = (*APIError)(nil)
+
+func (ae *APIError) Error() string {
+	if ae == nil {
+		return ""
+	}
+	var msgs []string
+	for _, err := range ae.Errors {
+		if s := err.Error(); s != "" {
+			msgs = append(msgs, s)
+		}
+	}
+	joined := strings.Join(msgs, "\n")
+	switch {
+	case ae.Status == "", ae.StatusCode/100 == 2:
+		return joined
+	case joined == "":
+		return ae.Status
+	default:
+		return ae.Status + ": " + joined
+	}
+}
+
+// Is reports whether ae matches one of the sentinel errors such as
+// ErrUnauthorized or ErrRateLimited, so that callers can do:
+//
+//	errors.Is(err, seedco.ErrRateLimited)
+func (ae *APIError) Is(target error) bool {
+	if ae == nil {
+		return false
+	}
+	switch target {
+	case ErrUnauthorized:
+		return ae.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return ae.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return ae.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return ae.StatusCode == http.StatusTooManyRequests
+	case ErrConflict:
+		return ae.StatusCode == http.StatusConflict || ae.StatusCode == http.StatusPreconditionFailed
+	case ErrServer:
+		return ae.StatusCode >= 500 && ae.StatusCode <= 599
+	default:
+		return false
+	}
+}
+
+// FieldErrors returns the errors that pertain to a specific field.
+func (ae *APIError) FieldErrors() map[string][]*Error {
+	if ae == nil {
+		return nil
+	}
+	fieldErrs := make(map[string][]*Error)
+	for _, err := range ae.Errors {
+		if err != nil && err.Field != "" {
+			fieldErrs[err.Field] = append(fieldErrs[err.Field], err)
+		}
+	}
+	return fieldErrs
+}
+
+var requestIDHeaders = []string{"X-Request-Id", "Request-Id", "X-Amzn-Requestid"}
+
+type errorsResponse struct {
+	Errors []*Error `json:"errors"`
+}
+
+// newAPIError builds the *APIError for a failed response, or one
+// that reported errors in its body, whose already read body is blob.
+func newAPIError(res *http.Response, blob []byte) *APIError {
+	ae := &APIError{
+		StatusCode: res.StatusCode,
+		Status:     res.Status,
+		Header:     res.Header,
+		Body:       blob,
+	}
+	if ae.Status == "" {
+		ae.Status = http.StatusText(res.StatusCode)
+	}
+	for _, key := range requestIDHeaders {
+		if id := res.Header.Get(key); id != "" {
+			ae.RequestID = id
+			break
+		}
+	}
+	er := new(errorsResponse)
+	if len(blob) > 0 && json.Unmarshal(blob, er) == nil {
+		ae.Errors = er.Errors
+	}
+	return ae
+}
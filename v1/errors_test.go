@@ -0,0 +1,121 @@
+package seedco_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/orijtech/seedco/v1"
+)
+
+func TestAPIError(t *testing.T) {
+	client, err := seedco.NewClientWithToken(token1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := [...]struct {
+		code     int
+		body     string
+		sentinel error
+
+		wantFieldErrs map[string][]*seedco.Error
+	}{
+		0: {code: http.StatusUnauthorized, sentinel: seedco.ErrUnauthorized},
+		1: {code: http.StatusForbidden, sentinel: seedco.ErrForbidden},
+		2: {code: http.StatusNotFound, body: `{"errors":[{"message":"no such account"}]}`, sentinel: seedco.ErrNotFound},
+		3: {code: http.StatusTooManyRequests, sentinel: seedco.ErrRateLimited},
+		4: {code: http.StatusBadGateway, body: "<html>bad gateway</html>", sentinel: seedco.ErrServer},
+		5: {
+			code: http.StatusUnprocessableEntity,
+			body: `{"errors":[{"message":"must be positive","field":"amount"}]}`,
+			wantFieldErrs: map[string][]*seedco.Error{
+				"amount": {{Message: "must be positive", Field: "amount"}},
+			},
+		},
+	}
+
+	sentinels := []error{
+		seedco.ErrUnauthorized, seedco.ErrForbidden, seedco.ErrNotFound,
+		seedco.ErrRateLimited, seedco.ErrServer,
+	}
+
+	for i, tt := range tests {
+		client.SetHTTPRoundTripper(&fixedResponder{code: tt.code, body: tt.body, requestID: "req-123"})
+		_, err := client.ListBalances()
+		if err == nil {
+			t.Errorf("#%d: want non-nil error", i)
+			continue
+		}
+		var ae *seedco.APIError
+		if !errors.As(err, &ae) {
+			t.Errorf("#%d: got %T want *seedco.APIError", i, err)
+			continue
+		}
+		if g, w := ae.StatusCode, tt.code; g != w {
+			t.Errorf("#%d: statusCode: got=%d want=%d", i, g, w)
+		}
+		if g, w := ae.RequestID, "req-123"; g != w {
+			t.Errorf("#%d: requestID: got=%q want=%q", i, g, w)
+		}
+		if g, w := string(ae.Body), tt.body; g != w {
+			t.Errorf("#%d: body: got=%q want=%q", i, g, w)
+		}
+		for _, sentinel := range sentinels {
+			if g, w := errors.Is(err, sentinel), sentinel == tt.sentinel; g != w {
+				t.Errorf("#%d: errors.Is(err, %v): got=%t want=%t", i, sentinel, g, w)
+			}
+		}
+		if tt.wantFieldErrs != nil {
+			if g, w := ae.FieldErrors(), tt.wantFieldErrs; !reflect.DeepEqual(g, w) {
+				t.Errorf("#%d: fieldErrors:\ngot: %+v\nwant:%+v", i, g, w)
+			}
+		}
+	}
+}
+
+func TestAPIErrorFromSuccessfulResponse(t *testing.T) {
+	client, err := seedco.NewClientWithToken(errToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetHTTPRoundTripper(&fixedResponder{code: http.StatusOK, body: `{"errors":[{"message":"bad balance"}]}`, requestID: "req-200"})
+
+	_, err = client.ListBalances()
+	var ae *seedco.APIError
+	if !errors.As(err, &ae) {
+		t.Fatalf("got %T (%v) want *seedco.APIError", err, err)
+	}
+	if g, w := ae.StatusCode, http.StatusOK; g != w {
+		t.Errorf("statusCode: got=%d want=%d", g, w)
+	}
+	if g, w := ae.RequestID, "req-200"; g != w {
+		t.Errorf("requestID: got=%q want=%q", g, w)
+	}
+	if ae.Header == nil {
+		t.Errorf("header: want the response header")
+	}
+	if g, w := ae.Error(), "bad balance"; g != w {
+		t.Errorf("message: got=%q want=%q", g, w)
+	}
+}
+
+// fixedResponder responds to every request with the same status code and body.
+type fixedResponder struct {
+	code      int
+	body      string
+	requestID string
+}
+
+var _ http.RoundTripper = (*fixedResponder)(nil)
+
+func (fr *fixedResponder) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := makeResp(http.StatusText(fr.code), fr.code, ioutil.NopCloser(strings.NewReader(fr.body)))
+	if fr.requestID != "" {
+		res.Header.Set("X-Request-Id", fr.requestID)
+	}
+	return res, err
+}
@@ -0,0 +1,125 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"github.com/orijtech/seedco/v1"
+)
+
+// Column is a column of CSV output.
+type Column string
+
+const (
+	ColumnID                Column = "id"
+	ColumnDate              Column = "date"
+	ColumnStatus            Column = "status"
+	ColumnAmount            Column = "amount"
+	ColumnDescription       Column = "description"
+	ColumnMemo              Column = "memo"
+	ColumnCategory          Column = "category"
+	ColumnCheckingAccountID Column = "checking_account_id"
+)
+
+// DefaultColumns are the columns of a CSVWriter without explicit columns.
+var DefaultColumns = []Column{
+	ColumnDate,
+	ColumnDescription,
+	ColumnAmount,
+	ColumnStatus,
+	ColumnCategory,
+	ColumnMemo,
+	ColumnID,
+}
+
+// CSVWriter writes transactions as CSV rows below a header row
+// naming the columns. Amounts are the signed decimal change to the
+// balance, such as -1234.56 for a debit; see Transaction.BalanceChange.
+type CSVWriter struct {
+	// DateLayout formats the date column. It defaults to "2006-01-02".
+	DateLayout string
+
+	cw          *csv.Writer
+	columns     []Column
+	wroteHeader bool
+}
+
+var _ Writer = (*CSVWriter)(nil)
+
+// NewCSVWriter returns a writer of the given columns, in order,
+// or of DefaultColumns if none are given.
+func NewCSVWriter(w io.Writer, columns ...Column) (*CSVWriter, error) {
+	if len(columns) == 0 {
+		columns = DefaultColumns
+	}
+	for _, col := range columns {
+		switch col {
+		case ColumnID, ColumnDate, ColumnStatus, ColumnAmount, ColumnDescription,
+			ColumnMemo, ColumnCategory, ColumnCheckingAccountID:
+		default:
+			return nil, fmt.Errorf("export: unknown CSV column %q", col)
+		}
+	}
+	return &CSVWriter{cw: csv.NewWriter(w), columns: columns}, nil
+}
+
+func (w *CSVWriter) writeHeader() error {
+	if w.wroteHeader {
+		return nil
+	}
+	w.wroteHeader = true
+	header := make([]string, len(w.columns))
+	for i, col := range w.columns {
+		header[i] = string(col)
+	}
+	return w.cw.Write(header)
+}
+
+func (w *CSVWriter) Write(t *seedco.Transaction) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	layout := w.DateLayout
+	if layout == "" {
+		layout = "2006-01-02"
+	}
+	amount, err := t.BalanceChange()
+	if err != nil {
+		return err
+	}
+	row := make([]string, len(w.columns))
+	for i, col := range w.columns {
+		switch col {
+		case ColumnID:
+			row[i] = t.ID
+		case ColumnDate:
+			if t.Date != nil {
+				row[i] = t.Date.Format(layout)
+			}
+		case ColumnStatus:
+			row[i] = string(t.Status)
+		case ColumnAmount:
+			row[i] = amount.Decimal()
+		case ColumnDescription:
+			row[i] = t.Description
+		case ColumnMemo:
+			row[i] = t.Memo
+		case ColumnCategory:
+			row[i] = string(t.Category)
+		case ColumnCheckingAccountID:
+			row[i] = t.CheckingAccountID
+		}
+	}
+	return w.cw.Write(row)
+}
+
+// Close writes the header if no transaction was
+// written, and flushes the buffered rows.
+func (w *CSVWriter) Close() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	w.cw.Flush()
+	return w.cw.Error()
+}
@@ -0,0 +1,79 @@
+package export_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/orijtech/seedco/v1"
+	"github.com/orijtech/seedco/v1/export"
+)
+
+func sampleTransactions() []*seedco.Transaction {
+	day := func(d int) *time.Time {
+		date := time.Date(2018, time.March, d, 15, 4, 5, 0, time.UTC)
+		return &date
+	}
+	return []*seedco.Transaction{
+		{ID: "t3", Date: day(3), Status: seedco.Pending, AmountCents: 990, Description: "Uber", Category: seedco.CategoryTravel},
+		{ID: "t2", Date: day(2), Status: seedco.Settled, AmountCents: -250000, Description: "Stripe payout", Memo: "March, week 1"},
+		{ID: "t1", Date: day(1), Status: seedco.Settled, AmountCents: 123456, Description: "Rent & <utilities>", Memo: "line one\nline two", Category: seedco.CategoryRent},
+	}
+}
+
+func writeAll(t *testing.T, w export.Writer, transactions []*seedco.Transaction) {
+	t.Helper()
+	for _, txn := range transactions {
+		if err := w.Write(txn); err != nil {
+			t.Fatalf("write %s: %v", txn.ID, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	tests := [...]struct {
+		columns    []export.Column
+		dateLayout string
+		want       string
+		wantErr    bool
+	}{
+		0: {
+			want: "date,description,amount,status,category,memo,id\n" +
+				"2018-03-03,Uber,-9.90,pending,Travel,,t3\n" +
+				"2018-03-02,Stripe payout,2500.00,settled,,\"March, week 1\",t2\n" +
+				"2018-03-01,Rent & <utilities>,-1234.56,settled,Rent,\"line one\nline two\",t1\n",
+		},
+		1: {
+			columns:    []export.Column{export.ColumnID, export.ColumnDate, export.ColumnAmount},
+			dateLayout: time.RFC3339,
+			want: "id,date,amount\n" +
+				"t3,2018-03-03T15:04:05Z,-9.90\n" +
+				"t2,2018-03-02T15:04:05Z,2500.00\n" +
+				"t1,2018-03-01T15:04:05Z,-1234.56\n",
+		},
+		2: {columns: []export.Column{"balance"}, wantErr: true},
+	}
+
+	for i, tt := range tests {
+		buf := new(bytes.Buffer)
+		w, err := export.NewCSVWriter(buf, tt.columns...)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: want non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+			continue
+		}
+		w.DateLayout = tt.dateLayout
+		writeAll(t, w, sampleTransactions())
+		if g, w := buf.String(), tt.want; g != w {
+			t.Errorf("#%d:\ngot=%q\nwant=%q", i, g, w)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Package export writes transactions in the formats that
+// accounting software imports: CSV, OFX 2.x and QIF.
+//
+// Each format has a Writer that transactions are streamed into,
+// for example straight from a search:
+//
+//	w := export.NewQIFWriter(f)
+//	n, err := export.Copy(ctx, w, client.Transactions(sp))
+//	if err == nil {
+//		err = w.Close()
+//	}
+package export
+
+import (
+	"context"
+
+	"github.com/orijtech/seedco/v1"
+)
+
+// Writer writes transactions one at a time.
+// Close must be called once every transaction was written; it
+// completes the output but does not close the underlying io.Writer.
+type Writer interface {
+	Write(t *seedco.Transaction) error
+	Close() error
+}
+
+// Copy writes every transaction that it pulls from it to w, and
+// returns how many were written. It does not close w.
+func Copy(ctx context.Context, w Writer, it *seedco.TransactionIterator) (int, error) {
+	n := 0
+	for {
+		t, err := it.Next(ctx)
+		if err == seedco.Done {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		if err := w.Write(t); err != nil {
+			return n, err
+		}
+		n += 1
+	}
+}
+
+// CopyPages is like Copy but reads the pages of a ListTransactions
+// search. It stops at the first page that carries an error, after
+// canceling the search.
+func CopyPages(w Writer, sr *seedco.SearchResults) (int, error) {
+	n := 0
+	for page := range sr.PagesChan {
+		if page.Err != nil {
+			_ = sr.Cancel()
+			return n, page.Err
+		}
+		for _, t := range page.Transactions {
+			if err := w.Write(t); err != nil {
+				_ = sr.Cancel()
+				return n, err
+			}
+			n += 1
+		}
+	}
+	return n, nil
+}
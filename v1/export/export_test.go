@@ -0,0 +1,147 @@
+package export_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orijtech/seedco/v1"
+	"github.com/orijtech/seedco/v1/export"
+	"github.com/orijtech/seedco/v1/seedcotest"
+)
+
+func TestCopy(t *testing.T) {
+	srv := seedcotest.NewUnstartedServer()
+	srv.AddTransactions(sampleTransactions()...)
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	w, err := export.NewCSVWriter(buf, export.ColumnID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := export.Copy(context.Background(), w, client.Transactions(&seedco.SearchParams{Limit: 2}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if g, w := n, 3; g != w {
+		t.Errorf("written: got=%d want=%d", g, w)
+	}
+	if g, w := buf.String(), "id\nt3\nt2\nt1\n"; g != w {
+		t.Errorf("got=%q want=%q", g, w)
+	}
+}
+
+func TestCopyPages(t *testing.T) {
+	srv := seedcotest.NewUnstartedServer()
+	srv.AddTransactions(sampleTransactions()...)
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	w := export.NewQIFWriter(buf)
+	sr, err := client.ListTransactions(&seedco.SearchParams{Status: seedco.Settled})
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := export.CopyPages(w, sr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if g, w := n, 2; g != w {
+		t.Errorf("written: got=%d want=%d", g, w)
+	}
+	if g, w := strings.Count(buf.String(), "^\n"), 2; g != w {
+		t.Errorf("records: got=%d want=%d", g, w)
+	}
+
+	// A failed page stops the copy.
+	srv.FailNext(seedcotest.TransactionsRoute, http.StatusForbidden)
+	sr, err = client.ListTransactions(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := export.CopyPages(export.NewQIFWriter(new(bytes.Buffer)), sr); err == nil {
+		t.Errorf("failed page: want non-nil error")
+	}
+}
+
+func fixtureTransactions(t *testing.T) []*seedco.Transaction {
+	t.Helper()
+	blob, err := os.ReadFile("../testdata/transactions-0,2.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp struct {
+		Results []*seedco.Transaction `json:"results"`
+	}
+	if err := json.Unmarshal(blob, &resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp.Results
+}
+
+// The purchases in the API's listing have positive amounts, and
+// are exported as debits that lower the balance.
+func TestExportFixtureSigns(t *testing.T) {
+	transactions := fixtureTransactions(t)
+
+	buf := new(bytes.Buffer)
+	cw, err := export.NewCSVWriter(buf, export.ColumnDescription, export.ColumnAmount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeAll(t, cw, transactions)
+	if g, w := buf.String(), "description,amount\nMcdonalds,-7.36\nUber to the cinema,-8.99\n"; g != w {
+		t.Errorf("CSV: got=%q want=%q", g, w)
+	}
+
+	buf.Reset()
+	writeAll(t, export.NewQIFWriter(buf), transactions)
+	if g := buf.String(); !strings.Contains(g, "\nT-7.36\n") || !strings.Contains(g, "\nT-8.99\n") {
+		t.Errorf("QIF: want negative amounts, got=%q", g)
+	}
+
+	buf.Reset()
+	ow, err := export.NewOFXWriter(buf, export.OFXOptions{
+		RoutingNumber:  "021000021",
+		AccountNumber:  "000123456789",
+		Start:          time.Date(2016, time.December, 1, 0, 0, 0, 0, time.UTC),
+		End:            time.Date(2017, time.October, 31, 0, 0, 0, 0, time.UTC),
+		IncludePending: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeAll(t, ow, transactions)
+	doc := new(ofxDoc)
+	if err := xml.Unmarshal(buf.Bytes(), doc); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, buf)
+	}
+	wantAmounts := []string{"-7.36", "-8.99"}
+	if g, w := len(doc.Transactions), len(wantAmounts); g != w {
+		t.Fatalf("OFX transactions: got=%d want=%d", g, w)
+	}
+	for i, txn := range doc.Transactions {
+		if txn.Type != "DEBIT" || txn.Amount != wantAmounts[i] {
+			t.Errorf("OFX #%d: got=(%s, %s) want=(DEBIT, %s)", i, txn.Type, txn.Amount, wantAmounts[i])
+		}
+	}
+}
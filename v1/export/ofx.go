@@ -0,0 +1,159 @@
+package export
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/orijtech/seedco/v1"
+)
+
+// OFXOptions describes the account and period of an OFX statement.
+type OFXOptions struct {
+	// RoutingNumber and AccountNumber identify the account,
+	// see seedco.CheckingAccount. Both are required.
+	RoutingNumber string
+	AccountNumber string
+
+	// Start and End bound the statement period. They are required
+	// since the period precedes the transactions in the output.
+	Start time.Time
+	End   time.Time
+
+	// LedgerBalance is the balance at End, which OFX requires.
+	LedgerBalance seedco.Cents
+
+	// IncludePending also writes pending transactions. OFX has no
+	// notion of pending transactions, so by default they are left
+	// out; if included, importers that match on the transaction ID
+	// update them once they settle.
+	IncludePending bool
+}
+
+// OFXWriter writes transactions as an OFX 2.2 bank statement,
+// the format that QuickBooks and Xero import as .ofx or .qfx.
+type OFXWriter struct {
+	opts        OFXOptions
+	bw          *bufio.Writer
+	wroteHeader bool
+}
+
+var _ Writer = (*OFXWriter)(nil)
+
+var (
+	errBlankOFXAccount = errors.New("export: OFX needs an AccountNumber")
+	errBlankOFXBank    = errors.New("export: OFX needs a RoutingNumber")
+	errOFXPeriod       = errors.New("export: OFX needs a Start that is not after End")
+)
+
+func NewOFXWriter(w io.Writer, opts OFXOptions) (*OFXWriter, error) {
+	if opts.AccountNumber == "" {
+		return nil, errBlankOFXAccount
+	}
+	if opts.RoutingNumber == "" {
+		return nil, errBlankOFXBank
+	}
+	if opts.Start.IsZero() || opts.End.IsZero() || opts.Start.After(opts.End) {
+		return nil, errOFXPeriod
+	}
+	return &OFXWriter{opts: opts, bw: bufio.NewWriter(w)}, nil
+}
+
+const ofxDateLayout = "20060102150405"
+
+func ofxDate(t time.Time) string {
+	return t.UTC().Format(ofxDateLayout) + "[0:GMT]"
+}
+
+// ofxText escapes s and truncates it to at most max characters
+// of output, without cutting an entity such as "&amp;" in half.
+func ofxText(s string, max int) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range s {
+		var esc strings.Builder
+		_ = xml.EscapeText(&esc, []byte(string(r)))
+		width := len([]rune(esc.String()))
+		if n+width > max {
+			break
+		}
+		n += width
+		b.WriteString(esc.String())
+	}
+	return b.String()
+}
+
+func (w *OFXWriter) header() string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="no"?>` + "\n")
+	b.WriteString(`<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n")
+	b.WriteString("<OFX>\n")
+	b.WriteString("<SIGNONMSGSRSV1><SONRS>")
+	b.WriteString("<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>")
+	b.WriteString("<DTSERVER>" + ofxDate(time.Now()) + "</DTSERVER><LANGUAGE>ENG</LANGUAGE>")
+	b.WriteString("</SONRS></SIGNONMSGSRSV1>\n")
+	b.WriteString("<BANKMSGSRSV1><STMTTRNRS><TRNUID>0</TRNUID>")
+	b.WriteString("<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>\n")
+	b.WriteString("<STMTRS><CURDEF>USD</CURDEF>\n")
+	b.WriteString("<BANKACCTFROM>")
+	b.WriteString("<BANKID>" + ofxText(w.opts.RoutingNumber, 9) + "</BANKID>")
+	b.WriteString("<ACCTID>" + ofxText(w.opts.AccountNumber, 22) + "</ACCTID><ACCTTYPE>CHECKING</ACCTTYPE>")
+	b.WriteString("</BANKACCTFROM>\n")
+	b.WriteString("<BANKTRANLIST><DTSTART>" + ofxDate(w.opts.Start) + "</DTSTART><DTEND>" + ofxDate(w.opts.End) + "</DTEND>\n")
+	return b.String()
+}
+
+func (w *OFXWriter) Write(t *seedco.Transaction) error {
+	if t.Status == seedco.Pending && !w.opts.IncludePending {
+		return nil
+	}
+	// TRNAMT is negative for debits.
+	amount, err := t.BalanceChange()
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		b.WriteString(w.header())
+	}
+	trnType := "CREDIT"
+	if t.IsDebit() {
+		trnType = "DEBIT"
+	}
+	b.WriteString("<STMTTRN><TRNTYPE>" + trnType + "</TRNTYPE>")
+	if t.Date != nil {
+		b.WriteString("<DTPOSTED>" + ofxDate(*t.Date) + "</DTPOSTED>")
+	}
+	b.WriteString("<TRNAMT>" + amount.Decimal() + "</TRNAMT>")
+	b.WriteString("<FITID>" + ofxText(t.ID, 255) + "</FITID>")
+	if t.Description != "" {
+		b.WriteString("<NAME>" + ofxText(t.Description, 32) + "</NAME>")
+	}
+	if t.Memo != "" {
+		b.WriteString("<MEMO>" + ofxText(t.Memo, 255) + "</MEMO>")
+	}
+	b.WriteString("</STMTTRN>\n")
+	_, err = w.bw.WriteString(b.String())
+	return err
+}
+
+// Close writes the end of the statement, with
+// the ledger balance, and flushes the output.
+func (w *OFXWriter) Close() error {
+	var b strings.Builder
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		b.WriteString(w.header())
+	}
+	b.WriteString("</BANKTRANLIST>\n")
+	b.WriteString("<LEDGERBAL><BALAMT>" + w.opts.LedgerBalance.Decimal() + "</BALAMT><DTASOF>" + ofxDate(w.opts.End) + "</DTASOF></LEDGERBAL>\n")
+	b.WriteString("</STMTRS></STMTTRNRS></BANKMSGSRSV1>\n</OFX>\n")
+	if _, err := w.bw.WriteString(b.String()); err != nil {
+		return err
+	}
+	return w.bw.Flush()
+}
@@ -0,0 +1,126 @@
+package export_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orijtech/seedco/v1"
+	"github.com/orijtech/seedco/v1/export"
+)
+
+type ofxDoc struct {
+	Account struct {
+		BankID string `xml:"BANKID"`
+		AcctID string `xml:"ACCTID"`
+	} `xml:"BANKMSGSRSV1>STMTTRNRS>STMTRS>BANKACCTFROM"`
+	Start        string `xml:"BANKMSGSRSV1>STMTTRNRS>STMTRS>BANKTRANLIST>DTSTART"`
+	Transactions []struct {
+		Type   string `xml:"TRNTYPE"`
+		Posted string `xml:"DTPOSTED"`
+		Amount string `xml:"TRNAMT"`
+		FITID  string `xml:"FITID"`
+		Name   string `xml:"NAME"`
+		Memo   string `xml:"MEMO"`
+	} `xml:"BANKMSGSRSV1>STMTTRNRS>STMTRS>BANKTRANLIST>STMTTRN"`
+	LedgerBalance string `xml:"BANKMSGSRSV1>STMTTRNRS>STMTRS>LEDGERBAL>BALAMT"`
+}
+
+func TestOFXWriter(t *testing.T) {
+	opts := export.OFXOptions{
+		RoutingNumber: "021000021",
+		AccountNumber: "000123456789",
+		Start:         time.Date(2018, time.March, 1, 0, 0, 0, 0, time.UTC),
+		End:           time.Date(2018, time.March, 31, 0, 0, 0, 0, time.UTC),
+		LedgerBalance: 1000000,
+	}
+
+	tests := [...]struct {
+		includePending bool
+		wantFITIDs     []string
+	}{
+		0: {wantFITIDs: []string{"t2", "t1"}},
+		1: {includePending: true, wantFITIDs: []string{"t3", "t2", "t1"}},
+	}
+
+	for i, tt := range tests {
+		buf := new(bytes.Buffer)
+		opts.IncludePending = tt.includePending
+		w, err := export.NewOFXWriter(buf, opts)
+		if err != nil {
+			t.Fatalf("#%d: unexpected error: %v", i, err)
+		}
+		writeAll(t, w, sampleTransactions())
+
+		doc := new(ofxDoc)
+		if err := xml.Unmarshal(buf.Bytes(), doc); err != nil {
+			t.Fatalf("#%d: invalid XML: %v\n%s", i, err, buf)
+		}
+		if g, w := doc.Account.AcctID, "000123456789"; g != w {
+			t.Errorf("#%d: ACCTID: got=%q want=%q", i, g, w)
+		}
+		if g, w := doc.Start, "20180301000000[0:GMT]"; g != w {
+			t.Errorf("#%d: DTSTART: got=%q want=%q", i, g, w)
+		}
+		if g, w := doc.LedgerBalance, "10000.00"; g != w {
+			t.Errorf("#%d: BALAMT: got=%q want=%q", i, g, w)
+		}
+		if g, w := len(doc.Transactions), len(tt.wantFITIDs); g != w {
+			t.Errorf("#%d: transactions: got=%d want=%d", i, g, w)
+			continue
+		}
+		for j, txn := range doc.Transactions {
+			if g, w := txn.FITID, tt.wantFITIDs[j]; g != w {
+				t.Errorf("#%d: #%d: FITID: got=%q want=%q", i, j, g, w)
+			}
+		}
+		last := doc.Transactions[len(doc.Transactions)-1]
+		if last.Type != "DEBIT" || last.Amount != "-1234.56" || last.Posted != "20180301150405[0:GMT]" {
+			t.Errorf("#%d: t1: got=%+v", i, last)
+		}
+		if g, w := last.Name, "Rent & <utilities>"; g != w {
+			t.Errorf("#%d: NAME: got=%q want=%q", i, g, w)
+		}
+		if g, w := doc.Transactions[len(doc.Transactions)-2].Type, "CREDIT"; g != w {
+			t.Errorf("#%d: t2: TRNTYPE: got=%q want=%q", i, g, w)
+		}
+	}
+
+	if _, err := export.NewOFXWriter(new(bytes.Buffer), export.OFXOptions{AccountNumber: "1234"}); err == nil {
+		t.Errorf("no period: want non-nil error")
+	}
+	if _, err := export.NewOFXWriter(new(bytes.Buffer), export.OFXOptions{Start: opts.Start, End: opts.End}); err == nil {
+		t.Errorf("no account: want non-nil error")
+	}
+	if _, err := export.NewOFXWriter(new(bytes.Buffer), export.OFXOptions{AccountNumber: "1234", Start: opts.Start, End: opts.End}); err == nil {
+		t.Errorf("no routing number: want non-nil error")
+	}
+}
+
+func TestOFXWriterTruncatesEscapedNames(t *testing.T) {
+	opts := export.OFXOptions{
+		RoutingNumber: "021000021",
+		AccountNumber: "000123456789",
+		Start:         time.Date(2018, time.March, 1, 0, 0, 0, 0, time.UTC),
+		End:           time.Date(2018, time.March, 31, 0, 0, 0, 0, time.UTC),
+	}
+	buf := new(bytes.Buffer)
+	w, err := export.NewOFXWriter(buf, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	date := opts.Start
+	// Cut at 32 characters, the escaped name would
+	// end in the middle of the last "&amp;".
+	name := "Smith & Sons & Daughtr & Co"
+	writeAll(t, w, []*seedco.Transaction{{ID: "t1", Date: &date, Status: seedco.Settled, Description: name}})
+
+	raw := buf.String()
+	start := strings.Index(raw, "<NAME>") + len("<NAME>")
+	end := strings.Index(raw, "</NAME>")
+	if g, w := raw[start:end], "Smith &amp; Sons &amp; Daughtr "; g != w {
+		t.Errorf("NAME: got=%q want=%q", g, w)
+	}
+}
@@ -0,0 +1,81 @@
+package export
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/orijtech/seedco/v1"
+)
+
+// QIFWriter writes transactions as a QIF bank account register.
+// Settled transactions are marked as cleared and pending ones are
+// left uncleared, so that they can be matched once they settle.
+type QIFWriter struct {
+	bw          *bufio.Writer
+	wroteHeader bool
+}
+
+var _ Writer = (*QIFWriter)(nil)
+
+func NewQIFWriter(w io.Writer) *QIFWriter {
+	return &QIFWriter{bw: bufio.NewWriter(w)}
+}
+
+const qifHeader = "!Type:Bank\n"
+
+// qifLine strips the line breaks that would end a QIF field early.
+var qifLine = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ")
+
+func (w *QIFWriter) Write(t *seedco.Transaction) error {
+	// QIF amounts are negative for withdrawals.
+	amount, err := t.BalanceChange()
+	if err != nil {
+		return err
+	}
+	var rec strings.Builder
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		rec.WriteString(qifHeader)
+	}
+	field := func(code, value string) {
+		if value != "" {
+			rec.WriteString(code + qifLine.Replace(value) + "\n")
+		}
+	}
+	if t.Date != nil {
+		field("D", t.Date.Format("01/02/2006"))
+	}
+	field("T", amount.Decimal())
+	if t.Status == seedco.Settled {
+		field("C", "c")
+	}
+	// N is the check number, so the ID goes into the memo instead.
+	field("P", t.Description)
+	field("M", qifMemo(t))
+	field("L", string(t.Category))
+	rec.WriteString("^\n")
+	_, err = w.bw.WriteString(rec.String())
+	return err
+}
+
+// qifMemo is the memo of t followed by its ID, so
+// that imported records can be traced back to it.
+func qifMemo(t *seedco.Transaction) string {
+	if t.Memo == "" {
+		return "ID " + t.ID
+	}
+	return t.Memo + " (ID " + t.ID + ")"
+}
+
+// Close writes the header if no transaction was
+// written, and flushes the buffered records.
+func (w *QIFWriter) Close() error {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if _, err := w.bw.WriteString(qifHeader); err != nil {
+			return err
+		}
+	}
+	return w.bw.Flush()
+}
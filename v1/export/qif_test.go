@@ -0,0 +1,27 @@
+package export_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/orijtech/seedco/v1/export"
+)
+
+func TestQIFWriter(t *testing.T) {
+	buf := new(bytes.Buffer)
+	writeAll(t, export.NewQIFWriter(buf), sampleTransactions())
+
+	want := "!Type:Bank\n" +
+		"D03/03/2018\nT-9.90\nPUber\nMID t3\nLTravel\n^\n" +
+		"D03/02/2018\nT2500.00\nCc\nPStripe payout\nMMarch, week 1 (ID t2)\n^\n" +
+		"D03/01/2018\nT-1234.56\nCc\nPRent & <utilities>\nMline one line two (ID t1)\nLRent\n^\n"
+	if g, w := buf.String(), want; g != w {
+		t.Errorf("\ngot=%q\nwant=%q", g, w)
+	}
+
+	buf.Reset()
+	writeAll(t, export.NewQIFWriter(buf), nil)
+	if g, w := buf.String(), "!Type:Bank\n"; g != w {
+		t.Errorf("empty: got=%q want=%q", g, w)
+	}
+}
@@ -0,0 +1,113 @@
+package seedco
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+type idempotencyKeyCtxKey struct{}
+
+// scopedIdempotencyKey is a caller supplied key, bound
+// to the first request that is made with it.
+type scopedIdempotencyKey struct {
+	key string
+
+	mu          sync.Mutex
+	fingerprint string
+}
+
+var errIdempotencyKeyReused = errors.New("idempotency key was already used for a different request")
+
+// WithIdempotencyKey returns a copy of ctx whose next mutating request
+// carries key as its Idempotency-Key, instead of a generated one. Use
+// it to make a logical call safe to repeat, for example after a crash,
+// by passing the same key again. The key is bound to the method, URL
+// and body of that first request: repeating it with ctx sends the key
+// again, while any other mutating request made with ctx fails rather
+// than have the API replay the first one's result.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtxKey{}, &scopedIdempotencyKey{key: key})
+}
+
+// keyFor returns the key if req is the first request made
+// with it, or the same method, URL and body as that one.
+func (sk *scopedIdempotencyKey) keyFor(req *http.Request) (string, error) {
+	fingerprint, err := requestFingerprint(req)
+	if err != nil {
+		return "", err
+	}
+	sk.mu.Lock()
+	defer sk.mu.Unlock()
+	switch sk.fingerprint {
+	case "":
+		sk.fingerprint = fingerprint
+	case fingerprint:
+	default:
+		return "", fmt.Errorf("%w: %s %s", errIdempotencyKeyReused, req.Method, req.URL)
+	}
+	return sk.key, nil
+}
+
+func requestFingerprint(req *http.Request) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n", req.Method, req.URL)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return "", err
+		}
+		defer body.Close()
+		if _, err := io.Copy(h, body); err != nil {
+			return "", err
+		}
+	} else if req.Body != nil && req.Body != http.NoBody {
+		// The body cannot be read again, so no request can match it.
+		fmt.Fprintf(h, "%p", req)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// setIdempotencyKey makes every request other than GET, HEAD and OPTIONS
+// carry an Idempotency-Key. A key already set on req is kept, otherwise
+// the one from ctx is used or a new one is generated. Since it is set
+// once on req, every retry of the same call sends the same key.
+func setIdempotencyKey(ctx context.Context, req *http.Request) error {
+	switch req.Method {
+	case "", "GET", "HEAD", "OPTIONS":
+		return nil
+	}
+	if req.Header.Get(idempotencyKeyHeader) != "" {
+		return nil
+	}
+	key := ""
+	if sk, ok := ctx.Value(idempotencyKeyCtxKey{}).(*scopedIdempotencyKey); ok {
+		var err error
+		if key, err = sk.keyFor(req); err != nil {
+			return err
+		}
+	}
+	if key == "" {
+		key = newIdempotencyKey()
+	}
+	req.Header.Set(idempotencyKeyHeader, key)
+	return nil
+}
+
+// newIdempotencyKey returns a random version 4 UUID.
+func newIdempotencyKey() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	h := hex.EncodeToString(b[:])
+	return h[:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
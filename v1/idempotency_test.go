@@ -0,0 +1,152 @@
+package seedco_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/orijtech/seedco/v1"
+	"github.com/orijtech/seedco/v1/seedcotest"
+)
+
+type keyRecorder struct {
+	http.RoundTripper
+
+	mu   sync.Mutex
+	keys []string
+}
+
+func (kr *keyRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	kr.mu.Lock()
+	kr.keys = append(kr.keys, req.Header.Get("Idempotency-Key"))
+	kr.mu.Unlock()
+	return kr.RoundTripper.RoundTrip(req)
+}
+
+func (kr *keyRecorder) reset() []string {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	keys := kr.keys
+	kr.keys = nil
+	return keys
+}
+
+func TestIdempotencyKeys(t *testing.T) {
+	srv := seedcotest.NewUnstartedServer()
+	srv.AddTransactions(&seedco.Transaction{ID: "t1", Description: "Duvet"})
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := &keyRecorder{RoundTripper: srv.Transport()}
+	client.SetHTTPRoundTripper(rec)
+	rp := seedco.DefaultRetryPolicy()
+	rp.InitialBackoff = time.Millisecond
+	client.SetRetryPolicy(rp)
+	ctx := context.Background()
+	update := *new(seedco.TransactionUpdate).SetMemo("Bedroom")
+
+	// Reads carry no key.
+	if _, err := client.GetTransaction(ctx, "t1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if keys := rec.reset(); len(keys) != 1 || keys[0] != "" {
+		t.Errorf("GET: got keys %q want none", keys)
+	}
+
+	// Retries of the same call reuse its generated key.
+	srv.FailNext(seedcotest.TransactionsRoute, http.StatusServiceUnavailable)
+	if _, err := client.UpdateTransaction(ctx, "t1", update); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	retried := rec.reset()
+	if len(retried) != 2 {
+		t.Fatalf("attempts: got=%d want=2", len(retried))
+	}
+	if retried[0] == "" || retried[0] != retried[1] {
+		t.Errorf("retried PATCH: got keys %q want one non-blank key", retried)
+	}
+
+	// Separate calls get separate keys.
+	if _, err := client.UpdateTransaction(ctx, "t1", update); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if keys := rec.reset(); len(keys) != 1 || keys[0] == "" || keys[0] == retried[0] {
+		t.Errorf("second PATCH: got keys %q want a new key", keys)
+	}
+
+	// Callers can supply their own.
+	kctx := seedco.WithIdempotencyKey(ctx, "memo-update-1")
+	if _, err := client.UpdateTransaction(kctx, "t1", update); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if keys := rec.reset(); len(keys) != 1 || keys[0] != "memo-update-1" {
+		t.Errorf("supplied key: got keys %q want %q", keys, "memo-update-1")
+	}
+
+	// A key on the payment request takes precedence.
+	pr := &seedco.PaymentRequest{
+		Type:                  seedco.BookTransfer,
+		FromCheckingAccountID: "a1",
+		ToCheckingAccountID:   "a2",
+		AmountCents:           100,
+		IdempotencyKey:        "payment-1",
+	}
+	if _, err := client.CreatePayment(kctx, pr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if keys := rec.reset(); len(keys) != 1 || keys[0] != "payment-1" {
+		t.Errorf("payment: got keys %q want %q", keys, "payment-1")
+	}
+}
+
+func TestIdempotencyKeyScopedToOneCall(t *testing.T) {
+	srv := seedcotest.NewUnstartedServer()
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := &keyRecorder{RoundTripper: srv.Transport()}
+	client.SetHTTPRoundTripper(rec)
+	kctx := seedco.WithIdempotencyKey(context.Background(), "transfer-1")
+	pr := &seedco.PaymentRequest{
+		Type:                  seedco.BookTransfer,
+		FromCheckingAccountID: "a1",
+		ToCheckingAccountID:   "a2",
+		AmountCents:           100,
+	}
+
+	first, err := client.CreatePayment(kctx, pr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Repeating the same call sends the key again and is replayed.
+	again, err := client.CreatePayment(kctx, pr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again.ID != first.ID {
+		t.Errorf("repeated call: got payment %q want %q", again.ID, first.ID)
+	}
+	if keys := rec.reset(); len(keys) != 2 || keys[0] != "transfer-1" || keys[1] != "transfer-1" {
+		t.Errorf("repeated call: got keys %q want the supplied key twice", keys)
+	}
+
+	// A different payment must not be mistaken for the first one.
+	other := *pr
+	other.AmountCents = 200
+	if _, err := client.CreatePayment(kctx, &other); err == nil {
+		t.Errorf("different payment: want non-nil error")
+	}
+	if _, err := client.CreatePayee(kctx, &seedco.Payee{Name: "Acme Supplies", RoutingNumber: "021000021", AccountNumber: "000123456789"}); err == nil {
+		t.Errorf("different call: want non-nil error")
+	}
+	if keys := rec.reset(); len(keys) != 0 {
+		t.Errorf("rejected calls: got keys %q want no requests", keys)
+	}
+	if g, w := len(srv.Payments()), 1; g != w {
+		t.Errorf("payments: got=%d want=%d", g, w)
+	}
+}
@@ -0,0 +1,99 @@
+package seedco
+
+import (
+	"context"
+	"errors"
+)
+
+// Done is returned by TransactionIterator.Next
+// once there are no more transactions.
+var Done = errors.New("seedco: no more items in iterator")
+
+// TransactionIterator is a pull-based alternative to SearchResults.
+// It fetches pages lazily, only when Next runs out of buffered
+// transactions, so no goroutine is involved and an iterator that is
+// abandoned early leaks nothing.
+type TransactionIterator struct {
+	c  *Client
+	sp *SearchParams
+
+	buf        []*Transaction
+	pageNumber int64
+
+	// offset is the offset of the next transaction that Next returns.
+	offset int
+	err    error
+}
+
+// Transactions returns an iterator over the
+// transactions that match sp, starting at sp.Offset.
+func (c *Client) Transactions(sp *SearchParams) *TransactionIterator {
+	spc := sp.withDefaults()
+	return &TransactionIterator{c: c, sp: spc, offset: spc.Offset}
+}
+
+// Next returns the next transaction. It returns Done once the
+// results, or the pages allowed by MaxPageNumber, are exhausted.
+// After Next returns an error, every later call returns it too.
+func (it *TransactionIterator) Next(ctx context.Context) (*Transaction, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if it.err != nil {
+		return nil, it.err
+	}
+	if len(it.buf) == 0 {
+		if err := it.fetch(ctx); err != nil {
+			if err != Done && ctx.Err() != nil {
+				// The caller may want to resume with a live context.
+				return nil, err
+			}
+			it.err = err
+			return nil, err
+		}
+	}
+	t := it.buf[0]
+	it.buf = it.buf[1:]
+	it.offset += 1
+	return t, nil
+}
+
+func (it *TransactionIterator) fetch(ctx context.Context) error {
+	if max := it.sp.MaxPageNumber; max > 0 && it.pageNumber >= max {
+		return Done
+	}
+	spc := new(SearchParams)
+	*spc = *it.sp
+	spc.Offset = it.offset
+	transactions, err := it.c.fetchTransactionsPage(ctx, spc)
+	if err != nil {
+		return err
+	}
+	if len(transactions) == 0 {
+		return Done
+	}
+	it.buf = transactions
+	it.pageNumber += 1
+	return nil
+}
+
+// Offset returns the offset of the next transaction that Next would
+// return. Searching with the same parameters and SearchParams.Offset
+// set to this value resumes the iteration where it left off.
+func (it *TransactionIterator) Offset() int {
+	return it.offset
+}
+
+// PageNumber returns the number of pages fetched so far.
+func (it *TransactionIterator) PageNumber() int64 {
+	return it.pageNumber
+}
+
+// ResumeParams returns the SearchParams that resume the iteration
+// at Offset. Its MaxPageNumber counts pages afresh from there.
+func (it *TransactionIterator) ResumeParams() *SearchParams {
+	spc := new(SearchParams)
+	*spc = *it.sp
+	spc.Offset = it.offset
+	return spc
+}
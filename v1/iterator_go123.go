@@ -0,0 +1,32 @@
+//go:build go1.23
+
+package seedco
+
+import (
+	"context"
+	"iter"
+)
+
+// All returns a single-use iterator over the remaining transactions.
+// Iteration stops after the first error, which is yielded as is;
+// running out of transactions is not reported as an error.
+//
+//	for t, err := range it.All(ctx) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (it *TransactionIterator) All(ctx context.Context) iter.Seq2[*Transaction, error] {
+	return func(yield func(*Transaction, error) bool) {
+		for {
+			t, err := it.Next(ctx)
+			if err == Done {
+				return
+			}
+			if !yield(t, err) || err != nil {
+				return
+			}
+		}
+	}
+}
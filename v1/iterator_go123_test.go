@@ -0,0 +1,32 @@
+//go:build go1.23
+
+package seedco_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/orijtech/seedco/v1"
+)
+
+func TestTransactionIteratorAll(t *testing.T) {
+	client, err := seedco.NewClientWithToken(testToken1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: listTransactionsRoute})
+
+	n := 0
+	for txn, err := range client.Transactions(&seedco.SearchParams{Limit: 2}).All(context.Background()) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if txn == nil {
+			t.Errorf("unexpectedly got a nil transaction")
+		}
+		n += 1
+	}
+	if g, w := n, 4; g != w {
+		t.Errorf("itemCount: got=%d want=%d", g, w)
+	}
+}
@@ -0,0 +1,134 @@
+package seedco_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/orijtech/seedco/v1"
+	"github.com/orijtech/seedco/v1/seedcotest"
+)
+
+func TestTransactionIterator(t *testing.T) {
+	client, err := seedco.NewClientWithToken(testToken1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: listTransactionsRoute})
+
+	tests := [...]struct {
+		params        *seedco.SearchParams
+		wantCount     int
+		wantPageCount int64
+	}{
+		0: {&seedco.SearchParams{Limit: 2, Offset: 0, MaxPageNumber: 1}, 2, 1},
+		1: {&seedco.SearchParams{Limit: 2, Offset: 0}, 4, 2},
+	}
+
+	ctx := context.Background()
+	for i, tt := range tests {
+		it := client.Transactions(tt.params)
+		n := 0
+		for {
+			txn, err := it.Next(ctx)
+			if err == seedco.Done {
+				break
+			}
+			if err != nil {
+				t.Fatalf("#%d: unexpected error: %v", i, err)
+			}
+			if txn == nil {
+				t.Errorf("#%d: unexpectedly got a nil transaction", i)
+			}
+			n += 1
+		}
+		if g, w := n, tt.wantCount; g != w {
+			t.Errorf("#%d: itemCount: got=%d want=%d", i, g, w)
+		}
+		if g, w := it.PageNumber(), tt.wantPageCount; g != w {
+			t.Errorf("#%d: pageCount: got=%d want=%d", i, g, w)
+		}
+		if g, w := it.Offset(), tt.params.Offset+tt.wantCount; g != w {
+			t.Errorf("#%d: offset: got=%d want=%d", i, g, w)
+		}
+		if _, err := it.Next(ctx); err != seedco.Done {
+			t.Errorf("#%d: after exhaustion: got=(%v) want=(%v)", i, err, seedco.Done)
+		}
+	}
+}
+
+func TestTransactionIteratorResume(t *testing.T) {
+	client, err := seedco.NewClientWithToken(testToken1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: listTransactionsRoute})
+
+	ctx := context.Background()
+	it := client.Transactions(&seedco.SearchParams{Limit: 2})
+	var ids []string
+	for i := 0; i < 2; i++ {
+		txn, err := it.Next(ctx)
+		if err != nil {
+			t.Fatalf("#%d: unexpected error: %v", i, err)
+		}
+		ids = append(ids, txn.ID)
+	}
+
+	resumed := client.Transactions(it.ResumeParams())
+	for {
+		txn, err := resumed.Next(ctx)
+		if err == seedco.Done {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids = append(ids, txn.ID)
+	}
+	if g, w := len(ids), 4; g != w {
+		t.Errorf("itemCount: got=%d want=%d", g, w)
+	}
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if seen[id] {
+			t.Errorf("transaction %q was returned twice", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestTransactionIteratorCanceledContext(t *testing.T) {
+	client, err := seedco.NewClientWithToken(testToken1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: listTransactionsRoute})
+
+	it := client.Transactions(&seedco.SearchParams{Limit: 2})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := it.Next(ctx); err == nil || err == seedco.Done {
+		t.Fatalf("got=(%v) want a context error", err)
+	}
+	// A canceled context does not end the iteration for good.
+	if _, err := it.Next(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestTransactionIteratorNilContext(t *testing.T) {
+	srv := seedcotest.NewUnstartedServer()
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.FailNext(seedcotest.TransactionsRoute, http.StatusForbidden)
+
+	// A nil context is like context.Background, even when the fetch fails.
+	var ctx context.Context
+	it := client.Transactions(nil)
+	if _, err := it.Next(ctx); err == nil || err == seedco.Done {
+		t.Errorf("got=(%v) want the failed fetch's error", err)
+	}
+}
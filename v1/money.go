@@ -0,0 +1,247 @@
+package seedco
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Cents is an exact amount of money in minor units, that is
+// cents for USD. Summing Cents never drifts the way floats do,
+// and the arithmetic methods report overflow instead of wrapping.
+type Cents int64
+
+// USD is the currency of every amount that the Seed API reports.
+const USD = "USD"
+
+var (
+	ErrOverflow         = errors.New("seedco: amount overflows int64 cents")
+	ErrCurrencyMismatch = errors.New("seedco: amounts have different currencies")
+
+	errFractionalCents = errors.New("amount has a fraction of a cent")
+)
+
+// UnmarshalJSON accepts integral JSON numbers, including forms
+// such as 736.0 and 7.36e2, and numbers quoted as strings.
+func (c *Cents) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if bytes.Equal(b, []byte("null")) {
+		return nil
+	}
+	s := string(b)
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = strings.TrimSpace(unquoted)
+	}
+	parsed, err := ParseCents(s)
+	if err != nil {
+		return fmt.Errorf("seedco: cannot decode %s as cents: %v", b, err)
+	}
+	*c = parsed
+	return nil
+}
+
+// ParseCents parses a number of cents such as "736" or "736.0".
+// A fraction of a cent is an error rather than being rounded.
+func ParseCents(s string) (Cents, error) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return Cents(n), nil
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	if !r.IsInt() {
+		return 0, errFractionalCents
+	}
+	n := r.Num()
+	if !n.IsInt64() {
+		return 0, ErrOverflow
+	}
+	return Cents(n.Int64()), nil
+}
+
+// Add returns c+d or ErrOverflow.
+func (c Cents) Add(d Cents) (Cents, error) {
+	if (d > 0 && c > math.MaxInt64-d) || (d < 0 && c < math.MinInt64-d) {
+		return 0, ErrOverflow
+	}
+	return c + d, nil
+}
+
+// Sub returns c-d or ErrOverflow.
+func (c Cents) Sub(d Cents) (Cents, error) {
+	if (d < 0 && c > math.MaxInt64+d) || (d > 0 && c < math.MinInt64+d) {
+		return 0, ErrOverflow
+	}
+	return c - d, nil
+}
+
+// Mul returns c*n or ErrOverflow.
+func (c Cents) Mul(n int64) (Cents, error) {
+	if c == 0 || n == 0 {
+		return 0, nil
+	}
+	product := int64(c) * n
+	if product/n != int64(c) || (int64(c) == -1 && n == math.MinInt64) || (n == -1 && c == math.MinInt64) {
+		return 0, ErrOverflow
+	}
+	return Cents(product), nil
+}
+
+// Abs returns the absolute value of c or ErrOverflow.
+func (c Cents) Abs() (Cents, error) {
+	if c >= 0 {
+		return c, nil
+	}
+	if c == math.MinInt64 {
+		return 0, ErrOverflow
+	}
+	return -c, nil
+}
+
+// SumCents adds up amounts, reporting ErrOverflow if the total does not fit.
+func SumCents(amounts ...Cents) (Cents, error) {
+	var total Cents
+	var err error
+	for _, amt := range amounts {
+		if total, err = total.Add(amt); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+// String formats c as US dollars, such as "$1,234.56" or "-$0.05".
+func (c Cents) String() string {
+	return Money{Amount: c, Currency: USD}.String()
+}
+
+// Money is an amount of Cents in a given ISO 4217 currency.
+// A blank Currency is USD.
+type Money struct {
+	Amount   Cents  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// USDollars returns c as Money in USD.
+func USDollars(c Cents) Money {
+	return Money{Amount: c, Currency: USD}
+}
+
+// currency returns the upper case currency code of m.
+func (m Money) currency() string {
+	if m.Currency == "" {
+		return USD
+	}
+	return strings.ToUpper(m.Currency)
+}
+
+func (m Money) sameCurrency(o Money) bool {
+	return m.currency() == o.currency()
+}
+
+// Add returns m+o, ErrCurrencyMismatch or ErrOverflow.
+func (m Money) Add(o Money) (Money, error) {
+	if !m.sameCurrency(o) {
+		return Money{}, ErrCurrencyMismatch
+	}
+	sum, err := m.Amount.Add(o.Amount)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: sum, Currency: m.Currency}, nil
+}
+
+// Sub returns m-o, ErrCurrencyMismatch or ErrOverflow.
+func (m Money) Sub(o Money) (Money, error) {
+	if !m.sameCurrency(o) {
+		return Money{}, ErrCurrencyMismatch
+	}
+	diff, err := m.Amount.Sub(o.Amount)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: diff, Currency: m.Currency}, nil
+}
+
+// Cmp compares m and o, returning -1, 0 or +1,
+// or ErrCurrencyMismatch if they cannot be compared.
+func (m Money) Cmp(o Money) (int, error) {
+	if !m.sameCurrency(o) {
+		return 0, ErrCurrencyMismatch
+	}
+	switch {
+	case m.Amount < o.Amount:
+		return -1, nil
+	case m.Amount > o.Amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+var currencySymbols = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+}
+
+// String formats m with its currency symbol and thousands
+// separators, such as "$1,234.56", or with its currency code
+// if it has no well known symbol, such as "CAD 1,234.56".
+func (m Money) String() string {
+	currency := m.currency()
+	sign := ""
+	// Work with uint64 so that math.MinInt64 can be negated.
+	abs := uint64(m.Amount)
+	if m.Amount < 0 {
+		sign = "-"
+		abs = uint64(-(m.Amount + 1)) + 1
+	}
+	units, minor := abs/100, abs%100
+	formatted := fmt.Sprintf("%s.%02d", groupThousands(strconv.FormatUint(units, 10)), minor)
+	if symbol, ok := currencySymbols[currency]; ok {
+		return sign + symbol + formatted
+	}
+	return sign + currency + " " + formatted
+}
+
+// Decimal formats m as a plain decimal number of
+// major units such as "-1234.56", without any symbol.
+func (m Money) Decimal() string {
+	return m.Amount.Decimal()
+}
+
+// Decimal formats c as a plain decimal number of
+// dollars such as "-1234.56", without any symbol.
+func (c Cents) Decimal() string {
+	sign := ""
+	abs := uint64(c)
+	if c < 0 {
+		sign = "-"
+		abs = uint64(-(c + 1)) + 1
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, abs/100, abs%100)
+}
+
+func groupThousands(digits string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	var buf strings.Builder
+	lead := len(digits) % 3
+	if lead > 0 {
+		buf.WriteString(digits[:lead])
+	}
+	for i := lead; i < len(digits); i += 3 {
+		if buf.Len() > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(digits[i : i+3])
+	}
+	return buf.String()
+}
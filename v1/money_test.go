@@ -0,0 +1,131 @@
+package seedco_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/orijtech/seedco/v1"
+)
+
+func TestCentsUnmarshalJSON(t *testing.T) {
+	tests := [...]struct {
+		in      string
+		want    seedco.Cents
+		wantErr bool
+	}{
+		0: {in: `736`, want: 736},
+		1: {in: `-736`, want: -736},
+		2: {in: `736.0`, want: 736},
+		3: {in: `7.36e2`, want: 736},
+		4: {in: `"736"`, want: 736},
+		5: {in: `null`, want: 0},
+		6: {in: `736.5`, wantErr: true},
+		7: {in: `"seven"`, wantErr: true},
+		8: {in: `1e19`, wantErr: true},
+		9: {in: `9223372036854775807`, want: math.MaxInt64},
+	}
+
+	for i, tt := range tests {
+		var got seedco.Cents
+		err := json.Unmarshal([]byte(tt.in), &got)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: want non-nil error, got %d", i, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("#%d: got=%d want=%d", i, got, tt.want)
+		}
+	}
+}
+
+func TestCentsArithmeticOverflow(t *testing.T) {
+	if _, err := seedco.Cents(math.MaxInt64).Add(1); err != seedco.ErrOverflow {
+		t.Errorf("MaxInt64+1: got=(%v) want=(%v)", err, seedco.ErrOverflow)
+	}
+	if _, err := seedco.Cents(math.MinInt64).Sub(1); err != seedco.ErrOverflow {
+		t.Errorf("MinInt64-1: got=(%v) want=(%v)", err, seedco.ErrOverflow)
+	}
+	if _, err := seedco.Cents(math.MinInt64).Mul(-1); err != seedco.ErrOverflow {
+		t.Errorf("MinInt64*-1: got=(%v) want=(%v)", err, seedco.ErrOverflow)
+	}
+	if _, err := seedco.Cents(math.MaxInt64 / 2).Mul(3); err != seedco.ErrOverflow {
+		t.Errorf("MaxInt64/2*3: got=(%v) want=(%v)", err, seedco.ErrOverflow)
+	}
+	if _, err := seedco.Cents(math.MinInt64).Abs(); err != seedco.ErrOverflow {
+		t.Errorf("|MinInt64|: got=(%v) want=(%v)", err, seedco.ErrOverflow)
+	}
+	if got, err := seedco.Cents(-25).Mul(4); err != nil || got != -100 {
+		t.Errorf("-25*4: got=(%d, %v) want=(-100, nil)", got, err)
+	}
+
+	// Summing many amounts that are not representable in binary
+	// floating point must still be exact.
+	amounts := make([]seedco.Cents, 10000)
+	for i := range amounts {
+		amounts[i] = 10
+	}
+	if got, err := seedco.SumCents(amounts...); err != nil || got != 100000 {
+		t.Errorf("sum: got=(%d, %v) want=(100000, nil)", got, err)
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := [...]struct {
+		money seedco.Money
+		want  string
+	}{
+		0: {seedco.USDollars(123456), "$1,234.56"},
+		1: {seedco.USDollars(-5), "-$0.05"},
+		2: {seedco.USDollars(0), "$0.00"},
+		3: {seedco.USDollars(100000000), "$1,000,000.00"},
+		4: {seedco.Money{Amount: 99999, Currency: "cad"}, "CAD 999.99"},
+		5: {seedco.Money{Amount: 150, Currency: "EUR"}, "€1.50"},
+		6: {seedco.USDollars(math.MinInt64), "-$92,233,720,368,547,758.08"},
+	}
+
+	for i, tt := range tests {
+		if g, w := tt.money.String(), tt.want; g != w {
+			t.Errorf("#%d: got=%q want=%q", i, g, w)
+		}
+	}
+	if g, w := seedco.Cents(-123456).Decimal(), "-1234.56"; g != w {
+		t.Errorf("decimal: got=%q want=%q", g, w)
+	}
+}
+
+func TestMoneyCurrencyMismatch(t *testing.T) {
+	usd := seedco.USDollars(100)
+	eur := seedco.Money{Amount: 100, Currency: "EUR"}
+	if _, err := usd.Add(eur); err != seedco.ErrCurrencyMismatch {
+		t.Errorf("add: got=(%v) want=(%v)", err, seedco.ErrCurrencyMismatch)
+	}
+	if _, err := usd.Cmp(eur); err != seedco.ErrCurrencyMismatch {
+		t.Errorf("cmp: got=(%v) want=(%v)", err, seedco.ErrCurrencyMismatch)
+	}
+	sum, err := usd.Add(seedco.Money{Amount: 50, Currency: "usd"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g, w := sum, seedco.USDollars(150); g != w {
+		t.Errorf("sum: got=%v want=%v", g, w)
+	}
+
+	// A blank currency is USD, as String shows it.
+	blank := seedco.Money{Amount: 25}
+	if g, w := blank.String(), "$0.25"; g != w {
+		t.Errorf("blank currency: got=%q want=%q", g, w)
+	}
+	if c, err := usd.Cmp(blank); err != nil || c != 1 {
+		t.Errorf("cmp blank currency: got=(%d, %v) want=(1, nil)", c, err)
+	}
+	if _, err := eur.Add(blank); err != seedco.ErrCurrencyMismatch {
+		t.Errorf("add blank currency to EUR: got=(%v) want=(%v)", err, seedco.ErrCurrencyMismatch)
+	}
+}
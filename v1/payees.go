@@ -0,0 +1,299 @@
+package seedco
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Payee is a saved recipient of ACH and wire payments.
+type Payee struct {
+	ID    string `json:"id,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+
+	RoutingNumber string          `json:"routing_number,omitempty"`
+	AccountNumber string          `json:"account_number,omitempty"`
+	AccountType   BankAccountType `json:"account_type,omitempty"`
+
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// Counterparty returns the bank details of the payee.
+func (p *Payee) Counterparty() *Counterparty {
+	return &Counterparty{
+		Name:          p.Name,
+		RoutingNumber: p.RoutingNumber,
+		AccountNumber: p.AccountNumber,
+		AccountType:   p.AccountType,
+	}
+}
+
+var (
+	errBlankPayeeID       = errors.New("payee ID must be non-blank")
+	errBlankPayeeName     = errors.New("payee name must be non-blank")
+	errNilPayee           = errors.New("expecting a non-nil Payee")
+	errNoPayee            = errors.New("no payee received")
+	errRoutingNumberLen   = errors.New("routing numbers have exactly 9 digits")
+	errRoutingNumberDigit = errors.New("routing numbers only contain digits")
+	errRoutingChecksum    = errors.New("routing number fails the ABA checksum")
+	errAccountNumber      = errors.New("account numbers have 4 to 17 digits")
+	errUnknownAccountType = errors.New("unknown bank account type")
+)
+
+// ValidateRoutingNumber reports whether rn is a well formed ABA routing
+// number, that is 9 digits whose weighted sum passes the ABA checksum.
+// It does not check that a bank is actually assigned the number.
+func ValidateRoutingNumber(rn string) error {
+	if len(rn) != 9 {
+		return errRoutingNumberLen
+	}
+	weights := [3]int{3, 7, 1}
+	sum := 0
+	for i, r := range rn {
+		if r < '0' || r > '9' {
+			return errRoutingNumberDigit
+		}
+		sum += int(r-'0') * weights[i%3]
+	}
+	if sum%10 != 0 {
+		return errRoutingChecksum
+	}
+	return nil
+}
+
+func validateAccountNumber(an string) error {
+	if len(an) < 4 || len(an) > 17 {
+		return errAccountNumber
+	}
+	for _, r := range an {
+		if r < '0' || r > '9' {
+			return errAccountNumber
+		}
+	}
+	return nil
+}
+
+func validateAccountType(at BankAccountType) error {
+	switch at {
+	case "", CheckingAccountType, SavingsAccountType:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", errUnknownAccountType, at)
+	}
+}
+
+// Validate checks p for the mistakes that the API would reject,
+// including a routing number that fails the ABA checksum.
+func (p *Payee) Validate() error {
+	if p == nil {
+		return errNilPayee
+	}
+	if p.Name == "" {
+		return errBlankPayeeName
+	}
+	if err := ValidateRoutingNumber(p.RoutingNumber); err != nil {
+		return err
+	}
+	if err := validateAccountNumber(p.AccountNumber); err != nil {
+		return err
+	}
+	return validateAccountType(p.AccountType)
+}
+
+// PayeeUpdate is a partial update of a payee: only
+// the fields that are set are changed.
+type PayeeUpdate struct {
+	Name          *string          `json:"name,omitempty"`
+	Email         *string          `json:"email,omitempty"`
+	RoutingNumber *string          `json:"routing_number,omitempty"`
+	AccountNumber *string          `json:"account_number,omitempty"`
+	AccountType   *BankAccountType `json:"account_type,omitempty"`
+}
+
+func (pu *PayeeUpdate) SetName(name string) *PayeeUpdate {
+	pu.Name = &name
+	return pu
+}
+
+func (pu *PayeeUpdate) SetEmail(email string) *PayeeUpdate {
+	pu.Email = &email
+	return pu
+}
+
+// SetBankAccount changes the bank account that the payee is paid into.
+func (pu *PayeeUpdate) SetBankAccount(routingNumber, accountNumber string, accountType BankAccountType) *PayeeUpdate {
+	pu.RoutingNumber = &routingNumber
+	pu.AccountNumber = &accountNumber
+	pu.AccountType = &accountType
+	return pu
+}
+
+// Validate checks the fields that pu sets.
+func (pu *PayeeUpdate) Validate() error {
+	if pu.Name == nil && pu.Email == nil && pu.RoutingNumber == nil && pu.AccountNumber == nil && pu.AccountType == nil {
+		return errEmptyUpdate
+	}
+	if pu.Name != nil && *pu.Name == "" {
+		return errBlankPayeeName
+	}
+	if pu.RoutingNumber != nil {
+		if err := ValidateRoutingNumber(*pu.RoutingNumber); err != nil {
+			return err
+		}
+	}
+	if pu.AccountNumber != nil {
+		if err := validateAccountNumber(*pu.AccountNumber); err != nil {
+			return err
+		}
+	}
+	if pu.AccountType != nil {
+		return validateAccountType(*pu.AccountType)
+	}
+	return nil
+}
+
+type payeesResponse struct {
+	Payees []*Payee `json:"results"`
+	Errors []*Error `json:"errors"`
+}
+
+func (c *Client) payeesURL() string {
+	return fmt.Sprintf("%s/public/payees", c.BaseURL())
+}
+
+func (c *Client) payeeURL(id string) string {
+	return fmt.Sprintf("%s/%s", c.payeesURL(), url.PathEscape(id))
+}
+
+// CreatePayee validates p and saves it as a new payee.
+func (c *Client) CreatePayee(ctx context.Context, p *Payee) (*Payee, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return c.doSinglePayeeReq(ctx, "POST", c.payeesURL(), p)
+}
+
+// ListPayees returns all the saved payees.
+func (c *Client) ListPayees(ctx context.Context) ([]*Payee, error) {
+	return c.doPayeesReq(ctx, "GET", c.payeesURL(), nil)
+}
+
+// GetPayee returns the payee with the given ID.
+func (c *Client) GetPayee(ctx context.Context, id string) (*Payee, error) {
+	if id == "" {
+		return nil, errBlankPayeeID
+	}
+	return c.doSinglePayeeReq(ctx, "GET", c.payeeURL(id), nil)
+}
+
+// UpdatePayee applies update to the payee with the
+// given ID and returns the payee as updated.
+func (c *Client) UpdatePayee(ctx context.Context, id string, update PayeeUpdate) (*Payee, error) {
+	if id == "" {
+		return nil, errBlankPayeeID
+	}
+	if err := update.Validate(); err != nil {
+		return nil, err
+	}
+	return c.doSinglePayeeReq(ctx, "PATCH", c.payeeURL(id), update)
+}
+
+// DeletePayee deletes the payee with the given ID. Payments
+// that were already made to the payee are not affected.
+func (c *Client) DeletePayee(ctx context.Context, id string) error {
+	if id == "" {
+		return errBlankPayeeID
+	}
+	_, err := c.doPayeesReq(ctx, "DELETE", c.payeeURL(id), nil)
+	return err
+}
+
+func (c *Client) doPayeesReq(ctx context.Context, method, fullURL string, body interface{}) ([]*Payee, error) {
+	var rd io.Reader
+	if body != nil {
+		blob, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		rd = bytes.NewReader(blob)
+	}
+	req, err := http.NewRequest(method, fullURL, rd)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	blob, res, err := c.doAuthAndReq(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	// A successful DELETE may be answered with 204 No Content.
+	if method == "DELETE" && len(bytes.TrimSpace(blob)) == 0 {
+		return nil, nil
+	}
+	pr := new(payeesResponse)
+	if err := json.Unmarshal(blob, pr); err != nil {
+		return nil, err
+	}
+	if err := flattenErrs(res, blob, pr.Errors); err != nil {
+		return nil, err
+	}
+	return pr.Payees, nil
+}
+
+func (c *Client) doSinglePayeeReq(ctx context.Context, method, fullURL string, body interface{}) (*Payee, error) {
+	payees, err := c.doPayeesReq(ctx, method, fullURL, body)
+	if err != nil {
+		return nil, err
+	}
+	if len(payees) == 0 || payees[0] == nil {
+		return nil, errNoPayee
+	}
+	return payees[0], nil
+}
+
+// PayeeTransactions returns the payment history of p: the transactions,
+// newest first, whose description or memo mentions the payee's name.
+// sp, if non-nil, narrows down the search, for example by date.
+func (c *Client) PayeeTransactions(ctx context.Context, p *Payee, sp *SearchParams) ([]*Transaction, error) {
+	if p == nil {
+		return nil, errNilPayee
+	}
+	if p.Name == "" {
+		return nil, errBlankPayeeName
+	}
+	spc := sp.withDefaults()
+	spc.Query = p.Name
+	name := strings.ToLower(p.Name)
+
+	var history []*Transaction
+	it := c.Transactions(spc)
+	for {
+		t, err := it.Next(ctx)
+		if err == Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if strings.Contains(strings.ToLower(t.Description), name) || strings.Contains(strings.ToLower(t.Memo), name) {
+			history = append(history, t)
+		}
+	}
+	// The API does not define the order of the transactions it lists.
+	sort.SliceStable(history, func(i, j int) bool {
+		di, dj := history[i].Date, history[j].Date
+		return di != nil && (dj == nil || di.After(*dj))
+	})
+	return history, nil
+}
@@ -0,0 +1,231 @@
+package seedco_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orijtech/seedco/v1"
+	"github.com/orijtech/seedco/v1/seedcotest"
+)
+
+func TestValidateRoutingNumber(t *testing.T) {
+	tests := [...]struct {
+		rn      string
+		wantErr bool
+	}{
+		0: {rn: "021000021"},
+		1: {rn: "011000015"},
+		2: {rn: "121000358"},
+		3: {rn: "021000022", wantErr: true},
+		4: {rn: "02100002", wantErr: true},
+		5: {rn: "0210000211", wantErr: true},
+		6: {rn: "02100002a", wantErr: true},
+		7: {rn: "", wantErr: true},
+	}
+
+	for i, tt := range tests {
+		err := seedco.ValidateRoutingNumber(tt.rn)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: %q: want non-nil error", i, tt.rn)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: %q: unexpected error: %v", i, tt.rn, err)
+		}
+	}
+}
+
+func TestPayees(t *testing.T) {
+	srv := seedcotest.NewUnstartedServer()
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	// Invalid payees never reach the API.
+	invalid := &seedco.Payee{Name: "Acme Supplies", RoutingNumber: "021000022", AccountNumber: "000123456789"}
+	if _, err := client.CreatePayee(ctx, invalid); err == nil {
+		t.Errorf("bad checksum: want non-nil error")
+	}
+	if g := srv.Requests(seedcotest.PayeesRoute); g != 0 {
+		t.Errorf("requests for an invalid payee: got=%d want=0", g)
+	}
+
+	created, err := client.CreatePayee(ctx, &seedco.Payee{
+		Name:          "Acme Supplies",
+		RoutingNumber: "021000021",
+		AccountNumber: "000123456789",
+		AccountType:   seedco.CheckingAccountType,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.ID == "" {
+		t.Fatal("expected the payee to have an ID")
+	}
+
+	update := new(seedco.PayeeUpdate).SetEmail("billing@acme.example").SetBankAccount("011000015", "98765", seedco.SavingsAccountType)
+	updated, err := client.UpdatePayee(ctx, created.ID, *update)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g, w := updated.Name, "Acme Supplies"; g != w {
+		t.Errorf("name: got=%q want=%q", g, w)
+	}
+	if g, w := updated.RoutingNumber, "011000015"; g != w {
+		t.Errorf("routing number: got=%q want=%q", g, w)
+	}
+	if g, w := updated.AccountType, seedco.SavingsAccountType; g != w {
+		t.Errorf("account type: got=%q want=%q", g, w)
+	}
+	badUpdate := new(seedco.PayeeUpdate).SetBankAccount("123456789", "98765", seedco.CheckingAccountType)
+	if _, err := client.UpdatePayee(ctx, created.ID, *badUpdate); err == nil {
+		t.Errorf("bad routing number update: want non-nil error")
+	}
+
+	got, err := client.GetPayee(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g, w := got.Email, "billing@acme.example"; g != w {
+		t.Errorf("email: got=%q want=%q", g, w)
+	}
+
+	payees, err := client.ListPayees(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g, w := len(payees), 1; g != w {
+		t.Errorf("payees: got=%d want=%d", g, w)
+	}
+
+	if err := client.DeletePayee(ctx, created.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.GetPayee(ctx, created.ID); !errors.Is(err, seedco.ErrNotFound) {
+		t.Errorf("deleted payee: got=(%v) want=(%v)", err, seedco.ErrNotFound)
+	}
+	if err := client.DeletePayee(ctx, created.ID); !errors.Is(err, seedco.ErrNotFound) {
+		t.Errorf("deleting twice: got=(%v) want=(%v)", err, seedco.ErrNotFound)
+	}
+}
+
+// noContentBackend answers GET requests with body, or with no
+// results past the first page, and every other request with
+// 204 No Content.
+type noContentBackend struct {
+	body string
+}
+
+var _ http.RoundTripper = (*noContentBackend)(nil)
+
+func (b *noContentBackend) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == "GET" {
+		body := b.body
+		if offset := req.URL.Query().Get("offset"); offset != "" && offset != "0" {
+			body = `{"results":[]}`
+		}
+		return makeResp("200 OK", http.StatusOK, ioutil.NopCloser(strings.NewReader(body)))
+	}
+	return makeResp("204 No Content", http.StatusNoContent, http.NoBody)
+}
+
+func TestDeletePayeeNoContent(t *testing.T) {
+	client, err := seedco.NewClientWithToken(testToken1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetHTTPRoundTripper(new(noContentBackend))
+	if err := client.DeletePayee(context.Background(), "payee-1"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestPaymentToPayee(t *testing.T) {
+	srv := seedcotest.NewUnstartedServer()
+	acme := &seedco.Payee{ID: "acme", Name: "Acme Supplies", RoutingNumber: "021000021", AccountNumber: "000123456789"}
+	srv.AddPayees(acme)
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := client.CreatePayment(context.Background(), &seedco.PaymentRequest{
+		Type:                  seedco.ACH,
+		FromCheckingAccountID: "a1",
+		PayeeID:               "acme",
+		AmountCents:           9900,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Counterparty == nil || p.Counterparty.AccountNumber != acme.AccountNumber {
+		t.Errorf("counterparty: got=%+v want=%+v", p.Counterparty, acme.Counterparty())
+	}
+}
+
+func TestPayeeTransactions(t *testing.T) {
+	srv := seedcotest.NewUnstartedServer()
+	day := func(d int) *time.Time {
+		date := time.Date(2018, time.March, d, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+	srv.AddTransactions(
+		&seedco.Transaction{ID: "t1", Description: "ACH to ACME SUPPLIES", AmountCents: -9900, Date: day(1)},
+		&seedco.Transaction{ID: "t2", Description: "Uber", AmountCents: -1500, Date: day(2)},
+		&seedco.Transaction{ID: "t3", Description: "Wire", Memo: "Acme Supplies invoice 7", AmountCents: -50000, Date: day(3)},
+		&seedco.Transaction{ID: "t4", Description: "Refund", Category: "Acme Supplies", AmountCents: 300, Date: day(4)},
+	)
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	acme := &seedco.Payee{Name: "Acme Supplies"}
+
+	history, err := client.PayeeTransactions(context.Background(), acme, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var ids []string
+	for _, txn := range history {
+		ids = append(ids, txn.ID)
+	}
+	if g, w := len(ids), 2; g != w || ids[0] != "t3" || ids[1] != "t1" {
+		t.Errorf("history: got=%q want=[t3 t1]", ids)
+	}
+
+	history, err = client.PayeeTransactions(context.Background(), acme, &seedco.SearchParams{EndDate: *day(2)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(history) != 1 || history[0].ID != "t1" {
+		t.Errorf("bounded history: got=%+v want only t1", history)
+	}
+}
+
+func TestPayeeTransactionsNewestFirst(t *testing.T) {
+	client, err := seedco.NewClientWithToken(testToken1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Listed oldest first, like testdata/transactions-0,2.json.
+	client.SetHTTPRoundTripper(&noContentBackend{body: `{"results":[
+		{"id":"t1","description":"Acme Supplies","date":"2016-12-27T12:00:00Z"},
+		{"id":"t2","description":"Acme Supplies","date":"2017-10-10T13:17:00Z"}
+	]}`})
+	history, err := client.PayeeTransactions(context.Background(), &seedco.Payee{Name: "Acme Supplies"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(history) != 2 || history[0].ID != "t2" || history[1].ID != "t1" {
+		t.Errorf("history: got=%+v want=[t2 t1]", history)
+	}
+}
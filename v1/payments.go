@@ -0,0 +1,289 @@
+package seedco
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type PaymentType string
+
+const (
+	// ACH payments settle in one to three business days.
+	ACH PaymentType = "ach"
+
+	// Wire payments settle the same business day.
+	Wire PaymentType = "wire"
+
+	// BookTransfer moves money instantly between
+	// two checking accounts held at Seed.
+	BookTransfer PaymentType = "book"
+)
+
+type PaymentStatus string
+
+const (
+	PaymentScheduled  PaymentStatus = "scheduled"
+	PaymentPending    PaymentStatus = "pending"
+	PaymentProcessing PaymentStatus = "processing"
+	PaymentCompleted  PaymentStatus = "completed"
+	PaymentCanceled   PaymentStatus = "canceled"
+	PaymentFailed     PaymentStatus = "failed"
+	PaymentReturned   PaymentStatus = "returned"
+)
+
+type BankAccountType string
+
+const (
+	CheckingAccountType BankAccountType = "checking"
+	SavingsAccountType  BankAccountType = "savings"
+)
+
+// Counterparty is the external bank account that
+// an ACH or wire payment is sent to.
+type Counterparty struct {
+	Name          string          `json:"name,omitempty"`
+	RoutingNumber string          `json:"routing_number,omitempty"`
+	AccountNumber string          `json:"account_number,omitempty"`
+	AccountType   BankAccountType `json:"account_type,omitempty"`
+}
+
+type Payment struct {
+	ID     string        `json:"id,omitempty"`
+	Type   PaymentType   `json:"type,omitempty"`
+	Status PaymentStatus `json:"status,omitempty"`
+
+	// FromCheckingAccountID is the account that is debited.
+	FromCheckingAccountID string `json:"from_checking_account_id,omitempty"`
+
+	// ToCheckingAccountID is the account credited by a BookTransfer.
+	ToCheckingAccountID string `json:"to_checking_account_id,omitempty"`
+
+	// Counterparty is the recipient of ACH and wire payments.
+	Counterparty *Counterparty `json:"counterparty,omitempty"`
+
+	// PayeeID is set for payments made to a saved Payee.
+	PayeeID string `json:"payee_id,omitempty"`
+
+	AmountCents Cents  `json:"amount,omitempty"`
+	Memo        string `json:"memo,omitempty"`
+
+	// ScheduledFor, if set, is the date that the payment is sent on.
+	// Until then its Status is PaymentScheduled and its amount
+	// counts towards the Balance.ScheduledDebits of the account.
+	ScheduledFor *time.Time `json:"scheduled_for,omitempty"`
+
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// PaymentRequest describes a payment to create.
+type PaymentRequest struct {
+	Type PaymentType `json:"type"`
+
+	FromCheckingAccountID string        `json:"from_checking_account_id"`
+	ToCheckingAccountID   string        `json:"to_checking_account_id,omitempty"`
+	Counterparty          *Counterparty `json:"counterparty,omitempty"`
+
+	// PayeeID pays a saved Payee instead of the Counterparty.
+	PayeeID string `json:"payee_id,omitempty"`
+
+	AmountCents Cents  `json:"amount"`
+	Memo        string `json:"memo,omitempty"`
+
+	ScheduledFor *time.Time `json:"scheduled_for,omitempty"`
+
+	// IdempotencyKey makes creating the payment safe to retry: the API
+	// creates at most one payment per key. It takes precedence over
+	// WithIdempotencyKey and is generated if both are blank.
+	IdempotencyKey string `json:"-"`
+}
+
+type paymentsResponse struct {
+	Payments []*Payment `json:"results"`
+	Errors   []*Error   `json:"errors"`
+}
+
+var (
+	errBlankPaymentID      = errors.New("payment ID must be non-blank")
+	errNilPaymentRequest   = errors.New("expecting a non-nil PaymentRequest")
+	errNonPositiveAmount   = errors.New("amount must be positive")
+	errBlankFromAccount    = errors.New("from_checking_account_id must be non-blank")
+	errBlankToAccount      = errors.New("book transfers need a to_checking_account_id")
+	errSameAccounts        = errors.New("book transfers need two different accounts")
+	errMissingCounterparty = errors.New("ACH and wire payments need a counterparty or payee")
+	errUnknownPaymentType  = errors.New("unknown payment type")
+	errNoPayment           = errors.New("no payment received")
+)
+
+// Validate checks pr for the mistakes that the API would reject.
+func (pr *PaymentRequest) Validate() error {
+	if pr == nil {
+		return errNilPaymentRequest
+	}
+	if pr.AmountCents <= 0 {
+		return errNonPositiveAmount
+	}
+	if pr.FromCheckingAccountID == "" {
+		return errBlankFromAccount
+	}
+	switch pr.Type {
+	case BookTransfer:
+		if pr.ToCheckingAccountID == "" {
+			return errBlankToAccount
+		}
+		if pr.ToCheckingAccountID == pr.FromCheckingAccountID {
+			return errSameAccounts
+		}
+	case ACH, Wire:
+		if pr.PayeeID != "" {
+			return nil
+		}
+		cp := pr.Counterparty
+		if cp == nil || cp.Name == "" || cp.AccountNumber == "" || cp.RoutingNumber == "" {
+			return errMissingCounterparty
+		}
+		if err := ValidateRoutingNumber(cp.RoutingNumber); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: %q", errUnknownPaymentType, pr.Type)
+	}
+	return nil
+}
+
+func (c *Client) paymentsURL() string {
+	return fmt.Sprintf("%s/public/payments", c.BaseURL())
+}
+
+func (c *Client) paymentURL(id string) string {
+	return fmt.Sprintf("%s/%s", c.paymentsURL(), url.PathEscape(id))
+}
+
+// CreatePayment creates an ACH, wire or book transfer payment.
+func (c *Client) CreatePayment(ctx context.Context, pr *PaymentRequest) (*Payment, error) {
+	if err := pr.Validate(); err != nil {
+		return nil, err
+	}
+	blob, err := json.Marshal(pr)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("POST", c.paymentsURL(), bytes.NewReader(blob))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if pr.IdempotencyKey != "" {
+		req.Header.Set(idempotencyKeyHeader, pr.IdempotencyKey)
+	}
+	return c.doSinglePaymentReq(ctx, req)
+}
+
+// GetPayment returns the payment with the given ID.
+func (c *Client) GetPayment(ctx context.Context, id string) (*Payment, error) {
+	if id == "" {
+		return nil, errBlankPaymentID
+	}
+	req, err := http.NewRequest("GET", c.paymentURL(id), nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.doSinglePaymentReq(ctx, req)
+}
+
+// ListScheduledPayments returns the payments that are scheduled but not
+// sent yet. If checkingAccountID is non-blank, only the payments debiting
+// that account are returned.
+func (c *Client) ListScheduledPayments(ctx context.Context, checkingAccountID string) ([]*Payment, error) {
+	qv := url.Values{"status": {string(PaymentScheduled)}}
+	if checkingAccountID != "" {
+		qv.Set("checking_account_id", checkingAccountID)
+	}
+	req, err := http.NewRequest("GET", c.paymentsURL()+"?"+qv.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.doPaymentsReq(ctx, req)
+}
+
+// CancelPayment cancels a scheduled payment. Payments
+// that were already sent cannot be canceled.
+func (c *Client) CancelPayment(ctx context.Context, id string) (*Payment, error) {
+	if id == "" {
+		return nil, errBlankPaymentID
+	}
+	req, err := http.NewRequest("POST", c.paymentURL(id)+"/cancel", nil)
+	if err != nil {
+		return nil, err
+	}
+	blob, res, err := c.doAuthAndReq(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	// The cancellation may be acknowledged with no content,
+	// in which case the canceled payment is looked up.
+	if len(bytes.TrimSpace(blob)) == 0 {
+		return c.GetPayment(ctx, id)
+	}
+	payments, err := parsePayments(blob, res)
+	if err != nil {
+		return nil, err
+	}
+	if len(payments) == 0 || payments[0] == nil {
+		return nil, errNoPayment
+	}
+	return payments[0], nil
+}
+
+func (c *Client) doPaymentsReq(ctx context.Context, req *http.Request) ([]*Payment, error) {
+	blob, res, err := c.doAuthAndReq(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return parsePayments(blob, res)
+}
+
+func parsePayments(blob []byte, res *http.Response) ([]*Payment, error) {
+	pr := new(paymentsResponse)
+	if err := json.Unmarshal(blob, pr); err != nil {
+		return nil, err
+	}
+	if err := flattenErrs(res, blob, pr.Errors); err != nil {
+		return nil, err
+	}
+	return pr.Payments, nil
+}
+
+func (c *Client) doSinglePaymentReq(ctx context.Context, req *http.Request) (*Payment, error) {
+	payments, err := c.doPaymentsReq(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(payments) == 0 || payments[0] == nil {
+		return nil, errNoPayment
+	}
+	return payments[0], nil
+}
+
+// ScheduledDebits sums the amounts of the scheduled payments that
+// debit checkingAccountID, which is what Balance.ScheduledDebits
+// of that account should report.
+func ScheduledDebits(payments []*Payment, checkingAccountID string) (Cents, error) {
+	var total Cents
+	var err error
+	for _, p := range payments {
+		if p == nil || p.Status != PaymentScheduled || p.FromCheckingAccountID != checkingAccountID {
+			continue
+		}
+		if total, err = total.Add(p.AmountCents); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,244 @@
+package seedco_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/orijtech/seedco/v1"
+	"github.com/orijtech/seedco/v1/seedcotest"
+)
+
+var vendor = &seedco.Counterparty{
+	Name:          "Acme Supplies",
+	RoutingNumber: "021000021",
+	AccountNumber: "000123456789",
+	AccountType:   seedco.CheckingAccountType,
+}
+
+func TestPaymentRequestValidate(t *testing.T) {
+	tests := [...]struct {
+		pr      *seedco.PaymentRequest
+		wantErr bool
+	}{
+		0: {pr: nil, wantErr: true},
+		1: {
+			pr: &seedco.PaymentRequest{Type: seedco.ACH, FromCheckingAccountID: "a1", Counterparty: vendor, AmountCents: 100},
+		},
+		2: {
+			// Non-positive amount.
+			pr:      &seedco.PaymentRequest{Type: seedco.ACH, FromCheckingAccountID: "a1", Counterparty: vendor},
+			wantErr: true,
+		},
+		3: {
+			// No counterparty.
+			pr:      &seedco.PaymentRequest{Type: seedco.Wire, FromCheckingAccountID: "a1", AmountCents: 100},
+			wantErr: true,
+		},
+		4: {
+			pr: &seedco.PaymentRequest{Type: seedco.BookTransfer, FromCheckingAccountID: "a1", ToCheckingAccountID: "a2", AmountCents: 100},
+		},
+		5: {
+			// Transferring to the same account.
+			pr:      &seedco.PaymentRequest{Type: seedco.BookTransfer, FromCheckingAccountID: "a1", ToCheckingAccountID: "a1", AmountCents: 100},
+			wantErr: true,
+		},
+		6: {
+			// No source account.
+			pr:      &seedco.PaymentRequest{Type: seedco.BookTransfer, ToCheckingAccountID: "a2", AmountCents: 100},
+			wantErr: true,
+		},
+		7: {
+			pr:      &seedco.PaymentRequest{Type: "check", FromCheckingAccountID: "a1", AmountCents: 100},
+			wantErr: true,
+		},
+	}
+
+	for i, tt := range tests {
+		err := tt.pr.Validate()
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: want non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+		}
+	}
+}
+
+func TestCreateAndGetPayment(t *testing.T) {
+	srv := seedcotest.NewUnstartedServer()
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	created, err := client.CreatePayment(ctx, &seedco.PaymentRequest{
+		Type:                  seedco.Wire,
+		FromCheckingAccountID: "a1",
+		Counterparty:          vendor,
+		AmountCents:           125000,
+		Memo:                  "Invoice 42",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.ID == "" {
+		t.Fatal("expected the payment to have an ID")
+	}
+	if g, w := created.Status, seedco.PaymentPending; g != w {
+		t.Errorf("status: got=%q want=%q", g, w)
+	}
+
+	got, err := client.GetPayment(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g, w := got.AmountCents, seedco.Cents(125000); g != w {
+		t.Errorf("amount: got=%d want=%d", g, w)
+	}
+	if got.Counterparty == nil || got.Counterparty.Name != vendor.Name {
+		t.Errorf("counterparty: got=%+v want=%+v", got.Counterparty, vendor)
+	}
+
+	if _, err := client.GetPayment(ctx, "unknown"); !errors.Is(err, seedco.ErrNotFound) {
+		t.Errorf("unknown payment: got=(%v) want=(%v)", err, seedco.ErrNotFound)
+	}
+	if _, err := client.GetPayment(ctx, ""); err == nil {
+		t.Errorf("blank ID: want non-nil error")
+	}
+}
+
+func TestCreatePaymentIdempotencyKey(t *testing.T) {
+	srv := seedcotest.NewUnstartedServer()
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rp := seedco.DefaultRetryPolicy()
+	rp.InitialBackoff = time.Millisecond
+	client.SetRetryPolicy(rp)
+	ctx := context.Background()
+
+	pr := &seedco.PaymentRequest{
+		Type:                  seedco.BookTransfer,
+		FromCheckingAccountID: "a1",
+		ToCheckingAccountID:   "a2",
+		AmountCents:           500,
+		IdempotencyKey:        "transfer-2018-03-01",
+	}
+
+	// Creations carry an idempotency key so they are retried.
+	srv.FailNext(seedcotest.PaymentsRoute, http.StatusBadGateway)
+	first, err := client.CreatePayment(ctx, pr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := client.CreatePayment(ctx, pr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.ID != second.ID {
+		t.Errorf("reused key: got two payments %q and %q", first.ID, second.ID)
+	}
+	if g, w := len(srv.Payments()), 1; g != w {
+		t.Errorf("payments created: got=%d want=%d", g, w)
+	}
+
+	// Without a caller supplied key each call creates a payment.
+	pr.IdempotencyKey = ""
+	for i := 0; i < 2; i++ {
+		if _, err := client.CreatePayment(ctx, pr); err != nil {
+			t.Fatalf("#%d: unexpected error: %v", i, err)
+		}
+	}
+	if g, w := len(srv.Payments()), 3; g != w {
+		t.Errorf("payments created: got=%d want=%d", g, w)
+	}
+}
+
+func TestScheduledPayments(t *testing.T) {
+	srv := seedcotest.NewUnstartedServer()
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	nextWeek := time.Now().Add(7 * 24 * time.Hour)
+	srv.AddPayments(
+		&seedco.Payment{ID: "p1", Type: seedco.ACH, Status: seedco.PaymentScheduled, FromCheckingAccountID: "a1", AmountCents: 1000, ScheduledFor: &nextWeek},
+		&seedco.Payment{ID: "p2", Type: seedco.ACH, Status: seedco.PaymentCompleted, FromCheckingAccountID: "a1", AmountCents: 2000},
+		&seedco.Payment{ID: "p3", Type: seedco.Wire, Status: seedco.PaymentScheduled, FromCheckingAccountID: "a2", AmountCents: 4000, ScheduledFor: &nextWeek},
+	)
+	created, err := client.CreatePayment(ctx, &seedco.PaymentRequest{
+		Type:                  seedco.ACH,
+		FromCheckingAccountID: "a1",
+		Counterparty:          vendor,
+		AmountCents:           250,
+		ScheduledFor:          &nextWeek,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g, w := created.Status, seedco.PaymentScheduled; g != w {
+		t.Errorf("status: got=%q want=%q", g, w)
+	}
+
+	scheduled, err := client.ListScheduledPayments(ctx, "a1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g, w := len(scheduled), 2; g != w {
+		t.Fatalf("scheduled payments: got=%d want=%d", g, w)
+	}
+	debits, err := seedco.ScheduledDebits(scheduled, "a1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g, w := debits, seedco.Cents(1250); g != w {
+		t.Errorf("scheduled debits: got=%d want=%d", g, w)
+	}
+
+	canceled, err := client.CancelPayment(ctx, "p1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g, w := canceled.Status, seedco.PaymentCanceled; g != w {
+		t.Errorf("status: got=%q want=%q", g, w)
+	}
+	if _, err := client.CancelPayment(ctx, "p1"); !errors.Is(err, seedco.ErrConflict) {
+		t.Errorf("canceling twice: got=(%v) want=(%v)", err, seedco.ErrConflict)
+	}
+	if _, err := client.CancelPayment(ctx, "p2"); !errors.Is(err, seedco.ErrConflict) {
+		t.Errorf("canceling a completed payment: got=(%v) want=(%v)", err, seedco.ErrConflict)
+	}
+
+	all, err := client.ListScheduledPayments(ctx, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g, w := len(all), 2; g != w {
+		t.Errorf("scheduled payments across accounts: got=%d want=%d", g, w)
+	}
+}
+
+func TestCancelPaymentNoContent(t *testing.T) {
+	client, err := seedco.NewClientWithToken(testToken1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetHTTPRoundTripper(&noContentBackend{body: `{"results":[{"id":"p1","status":"canceled"}]}`})
+	canceled, err := client.CancelPayment(context.Background(), "p1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if canceled.ID != "p1" || canceled.Status != seedco.PaymentCanceled {
+		t.Errorf("got=(%s, %s) want=(p1, canceled)", canceled.ID, canceled.Status)
+	}
+}
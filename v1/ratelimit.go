@@ -0,0 +1,190 @@
+package seedco
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimiter paces the requests that a Client sends. A single
+// RateLimiter can be shared by several clients so that they
+// draw from one budget; implementations must be safe for
+// concurrent use.
+type RateLimiter interface {
+	// Wait blocks until a request may be sent or ctx is done.
+	Wait(ctx context.Context) error
+}
+
+// ResponseObserver is optionally implemented by RateLimiters
+// that adapt to the rate limit headers of every response.
+type ResponseObserver interface {
+	ObserveResponse(statusCode int, hdr http.Header)
+}
+
+// Unlimited is a RateLimiter that never waits.
+var Unlimited RateLimiter = unlimited{}
+
+type unlimited struct{}
+
+func (unlimited) Wait(ctx context.Context) error { return ctx.Err() }
+
+// defaultRateLimitInterval and defaultRateLimitBurst are the pace
+// of a Client that was not given a RateLimiter explicitly.
+const (
+	defaultRateLimitInterval = 150 * time.Millisecond
+	defaultRateLimitBurst    = 10
+
+	// defaultMaxPause is how long a TokenBucket pauses at most
+	// unless told otherwise with SetMaxPause.
+	defaultMaxPause = time.Minute
+)
+
+// TokenBucket is a token bucket RateLimiter that slows down
+// when the API reports that few requests are left in the
+// current window through the X-RateLimit-Remaining and
+// X-RateLimit-Reset headers, and pauses after a 429 until
+// its Retry-After has passed. Pauses are capped at one minute
+// by default.
+type TokenBucket struct {
+	limiter *rate.Limiter
+	maxRate rate.Limit
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+	maxPause    time.Duration
+}
+
+var (
+	_ RateLimiter      = (*TokenBucket)(nil)
+	_ ResponseObserver = (*TokenBucket)(nil)
+)
+
+// NewTokenBucket returns a TokenBucket that allows perSecond
+// requests per second on average with bursts of up to burst.
+func NewTokenBucket(perSecond float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	maxRate := rate.Limit(perSecond)
+	return &TokenBucket{
+		limiter:  rate.NewLimiter(maxRate, burst),
+		maxRate:  maxRate,
+		maxPause: defaultMaxPause,
+	}
+}
+
+// SetMaxPause caps how long the bucket pauses after a 429 or an
+// exhausted window, however far ahead the server asks it to wait.
+// A non-positive d removes the cap.
+func (tb *TokenBucket) SetMaxPause(d time.Duration) {
+	tb.mu.Lock()
+	tb.maxPause = d
+	tb.mu.Unlock()
+}
+
+func (tb *TokenBucket) Wait(ctx context.Context) error {
+	tb.mu.Lock()
+	pause := time.Until(tb.pausedUntil)
+	tb.mu.Unlock()
+	if pause > 0 {
+		timer := time.NewTimer(pause)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+	return tb.limiter.Wait(ctx)
+}
+
+func (tb *TokenBucket) ObserveResponse(statusCode int, hdr http.Header) {
+	now := time.Now()
+	if statusCode == http.StatusTooManyRequests {
+		if d, ok := parseRetryAfter(hdr.Get("Retry-After"), now); ok {
+			tb.pauseUntil(now, now.Add(d))
+		}
+	}
+
+	remaining, err := strconv.Atoi(hdr.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		tb.limiter.SetLimitAt(now, tb.maxRate)
+		return
+	}
+	reset, ok := parseRateLimitReset(hdr.Get("X-RateLimit-Reset"), now)
+	if !ok {
+		tb.limiter.SetLimitAt(now, tb.maxRate)
+		return
+	}
+	if remaining <= 0 {
+		tb.pauseUntil(now, reset)
+		return
+	}
+	// Spread the requests that are left evenly over the rest of the window.
+	allowed := rate.Limit(float64(remaining) / reset.Sub(now).Seconds())
+	if allowed > tb.maxRate {
+		allowed = tb.maxRate
+	}
+	tb.limiter.SetLimitAt(now, allowed)
+}
+
+func (tb *TokenBucket) pauseUntil(now, t time.Time) {
+	tb.mu.Lock()
+	if tb.maxPause > 0 && t.Sub(now) > tb.maxPause {
+		t = now.Add(tb.maxPause)
+	}
+	if t.After(tb.pausedUntil) {
+		tb.pausedUntil = t
+	}
+	tb.mu.Unlock()
+}
+
+// parseRateLimitReset parses an X-RateLimit-Reset header
+// given either as a Unix timestamp or in seconds from now.
+func parseRateLimitReset(value string, now time.Time) (time.Time, bool) {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return time.Time{}, false
+	}
+	var reset time.Time
+	if n > 1e9 {
+		reset = time.Unix(n, 0)
+	} else {
+		reset = now.Add(time.Duration(n) * time.Second)
+	}
+	if !reset.After(now) {
+		return time.Time{}, false
+	}
+	return reset, true
+}
+
+// SetRateLimiter makes the client wait on rl before every request.
+// Passing the same rl to several clients makes them share its budget.
+// A nil rl restores the default of one request per 150ms
+// with bursts of up to 10; use
+// Unlimited to disable rate limiting.
+func (c *Client) SetRateLimiter(rl RateLimiter) {
+	c.mu.Lock()
+	c.rl = rl
+	c.mu.Unlock()
+}
+
+func (c *Client) rateLimiter() RateLimiter {
+	c.mu.RLock()
+	rl := c.rl
+	c.mu.RUnlock()
+	if rl != nil {
+		return rl
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.rl == nil {
+		c.rl = NewTokenBucket(float64(time.Second)/float64(defaultRateLimitInterval), defaultRateLimitBurst)
+	}
+	return c.rl
+}
@@ -0,0 +1,121 @@
+package seedco_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/orijtech/seedco/v1"
+)
+
+func TestSharedRateLimiter(t *testing.T) {
+	// 20 requests per second with no bursting: 6 requests
+	// split over two clients must take at least 250ms.
+	tb := seedco.NewTokenBucket(20, 1)
+
+	var clients []*seedco.Client
+	for i := 0; i < 2; i++ {
+		client, err := seedco.NewClientWithToken(token1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client.SetHTTPRoundTripper(&backend{route: listBalancesRoute})
+		client.SetRateLimiter(tb)
+		clients = append(clients, client)
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func(client *seedco.Client) {
+			defer wg.Done()
+			if _, err := client.ListBalances(); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(clients[i%len(clients)])
+	}
+	wg.Wait()
+
+	if elapsed := time.Since(start); elapsed < 250*time.Millisecond {
+		t.Errorf("6 requests took %v, want at least 250ms", elapsed)
+	}
+}
+
+func TestTokenBucketPausesWhenExhausted(t *testing.T) {
+	tb := seedco.NewTokenBucket(1000, 10)
+
+	hdr := make(http.Header)
+	hdr.Set("X-RateLimit-Remaining", "0")
+	hdr.Set("X-RateLimit-Reset", "1")
+	tb.ObserveResponse(http.StatusOK, hdr)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := tb.Wait(ctx); err == nil {
+		t.Errorf("want Wait to block until the window resets")
+	}
+}
+
+func TestTokenBucketPausesOnRetryAfter(t *testing.T) {
+	tb := seedco.NewTokenBucket(1000, 10)
+
+	hdr := make(http.Header)
+	hdr.Set("Retry-After", strconv.Itoa(2))
+	tb.ObserveResponse(http.StatusTooManyRequests, hdr)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := tb.Wait(ctx); err == nil {
+		t.Errorf("want Wait to block until Retry-After has passed")
+	}
+}
+
+func TestTokenBucketCapsPause(t *testing.T) {
+	tests := [...]struct {
+		name string
+		hdr  map[string]string
+		code int
+	}{
+		0: {name: "Retry-After", code: http.StatusTooManyRequests, hdr: map[string]string{"Retry-After": "86400"}},
+		1: {name: "X-RateLimit-Reset", code: http.StatusOK, hdr: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "86400"}},
+	}
+
+	for i, tt := range tests {
+		tb := seedco.NewTokenBucket(1000, 10)
+		tb.SetMaxPause(50 * time.Millisecond)
+		hdr := make(http.Header)
+		for k, v := range tt.hdr {
+			hdr.Set(k, v)
+		}
+		tb.ObserveResponse(tt.code, hdr)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		if err := tb.Wait(ctx); err != nil {
+			t.Errorf("#%d %s: want the pause capped, got %v", i, tt.name, err)
+		}
+		cancel()
+	}
+}
+
+func TestUnlimitedRateLimiter(t *testing.T) {
+	client, err := seedco.NewClientWithToken(token1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: listBalancesRoute})
+	client.SetRateLimiter(seedco.Unlimited)
+
+	start := time.Now()
+	for i := 0; i < 30; i++ {
+		if _, err := client.ListBalances(); err != nil {
+			t.Fatalf("#%d: unexpected error: %v", i, err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("30 unlimited requests took %v", elapsed)
+	}
+}
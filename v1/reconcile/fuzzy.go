@@ -0,0 +1,75 @@
+package reconcile
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/orijtech/seedco/v1"
+)
+
+// descriptionSimilarity compares the descriptions and memos of e and t,
+// returning the best similarity of any pair of non-blank ones.
+func descriptionSimilarity(e *Entry, t *seedco.Transaction) float64 {
+	return bestSimilarity([]string{e.Description, e.Memo}, []string{t.Description, t.Memo})
+}
+
+// entrySimilarity is like descriptionSimilarity for two entries.
+func entrySimilarity(e1, e2 *Entry) float64 {
+	return bestSimilarity([]string{e1.Description, e1.Memo}, []string{e2.Description, e2.Memo})
+}
+
+// transactionSimilarity is like descriptionSimilarity for two transactions.
+func transactionSimilarity(t1, t2 *seedco.Transaction) float64 {
+	return bestSimilarity([]string{t1.Description, t1.Memo}, []string{t2.Description, t2.Memo})
+}
+
+func bestSimilarity(as, bs []string) float64 {
+	best := 0.0
+	for _, a := range as {
+		for _, b := range bs {
+			if s := similarity(a, b); s > best {
+				best = s
+			}
+		}
+	}
+	return best
+}
+
+// similarity is the Sørensen–Dice coefficient of the character
+// bigrams of a and b, ignoring case, punctuation and spacing, so
+// that "UBER *TRIP 8H2K" and "Uber trip" are alike.
+func similarity(a, b string) float64 {
+	ba, bb := bigrams(a), bigrams(b)
+	if len(ba) == 0 || len(bb) == 0 {
+		return 0
+	}
+	counts := make(map[string]int, len(ba))
+	for _, bg := range ba {
+		counts[bg] += 1
+	}
+	shared := 0
+	for _, bg := range bb {
+		if counts[bg] > 0 {
+			counts[bg] -= 1
+			shared += 1
+		}
+	}
+	return 2 * float64(shared) / float64(len(ba)+len(bb))
+}
+
+func bigrams(s string) []string {
+	var runes []rune
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			runes = append(runes, r)
+		}
+	}
+	if len(runes) < 2 {
+		return nil
+	}
+	bgs := make([]string, 0, len(runes)-1)
+	for i := 0; i+1 < len(runes); i++ {
+		bgs = append(bgs, string(runes[i:i+2]))
+	}
+	return bgs
+}
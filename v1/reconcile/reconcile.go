@@ -0,0 +1,265 @@
+// Package reconcile matches Seed transactions against the entries
+// of a general ledger, reporting what matched, what is missing on
+// either side, what was recorded twice and where amounts disagree.
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/orijtech/seedco/v1"
+)
+
+// Entry is a general ledger entry for the bank account. Amounts are
+// signed as in the ledger: negative for money out of the account and
+// positive for money in. They are compared to the BalanceChange of
+// transactions, since the API signs transaction amounts the other
+// way around.
+type Entry struct {
+	ID          string
+	Date        time.Time
+	Amount      seedco.Cents
+	Description string
+	Memo        string
+}
+
+// Ledger provides the ledger entries to reconcile.
+type Ledger interface {
+	Entries(ctx context.Context) ([]*Entry, error)
+}
+
+// Entries is a Ledger of entries already in memory.
+type Entries []*Entry
+
+var _ Ledger = Entries(nil)
+
+func (es Entries) Entries(ctx context.Context) ([]*Entry, error) {
+	return es, nil
+}
+
+// Match pairs a ledger entry with a transaction.
+type Match struct {
+	Entry       *Entry
+	Transaction *seedco.Transaction
+
+	// Confidence, between 0 and 1, is how likely
+	// the entry and the transaction are the same.
+	Confidence float64
+
+	// Difference is the transaction's BalanceChange minus the
+	// entry's amount. It is only non-zero in Report.AmountMismatches.
+	Difference seedco.Cents
+}
+
+// Duplicate is a transaction or an entry, whichever is set, that
+// matches the same counterpart as Of and was likely recorded twice.
+type Duplicate struct {
+	Transaction *seedco.Transaction
+	Entry       *Entry
+	Of          *Match
+	Confidence  float64
+}
+
+type Report struct {
+	Matched []*Match
+
+	// AmountMismatches pair entries and transactions that look
+	// alike but whose amounts differ.
+	AmountMismatches []*Match
+
+	Duplicates []*Duplicate
+
+	UnmatchedTransactions []*seedco.Transaction
+	UnmatchedEntries      []*Entry
+}
+
+// Reconciler matches entries to transactions by amount, date and
+// description. Each entry matches at most one transaction.
+type Reconciler struct {
+	Ledger Ledger
+
+	// DateWindow is how far apart the dates of an entry and its
+	// transaction may be. It defaults to 3 days.
+	DateWindow time.Duration
+
+	// MinConfidence is the confidence below which entries and
+	// transactions are not paired. It defaults to 0.5.
+	MinConfidence float64
+
+	// IncludePending also reconciles pending transactions,
+	// which are skipped by default.
+	IncludePending bool
+}
+
+const (
+	defaultDateWindow    = 3 * 24 * time.Hour
+	defaultMinConfidence = 0.5
+
+	// mismatchSimilarity is the description similarity above
+	// which entries with different amounts are still paired.
+	mismatchSimilarity = 0.6
+
+	// duplicateEpsilon is how much less confident than the match
+	// it duplicates a differently described duplicate may be.
+	duplicateEpsilon = 0.05
+)
+
+var errNilLedger = errors.New("reconcile: expecting a non-nil Ledger")
+
+// Reconcile reconciles the ledger against the transactions of it.
+func (r *Reconciler) Reconcile(ctx context.Context, it *seedco.TransactionIterator) (*Report, error) {
+	var transactions []*seedco.Transaction
+	for {
+		t, err := it.Next(ctx)
+		if err == seedco.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, t)
+	}
+	return r.ReconcileTransactions(ctx, transactions)
+}
+
+// ReconcileTransactions is like Reconcile for transactions in memory.
+func (r *Reconciler) ReconcileTransactions(ctx context.Context, transactions []*seedco.Transaction) (*Report, error) {
+	if r.Ledger == nil {
+		return nil, errNilLedger
+	}
+	entries, err := r.Ledger.Entries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	window := r.DateWindow
+	if window <= 0 {
+		window = defaultDateWindow
+	}
+	minConfidence := r.MinConfidence
+	if minConfidence <= 0 {
+		minConfidence = defaultMinConfidence
+	}
+
+	var txns []*seedco.Transaction
+	for _, t := range transactions {
+		if t == nil || t.Date == nil || (t.Status == seedco.Pending && !r.IncludePending) {
+			continue
+		}
+		txns = append(txns, t)
+	}
+
+	// Score every pair within the date window.
+	var exact, near []*Match
+	for _, e := range entries {
+		if e == nil {
+			continue
+		}
+		for _, t := range txns {
+			change, err := t.BalanceChange()
+			if err != nil {
+				return nil, err
+			}
+			gap := t.Date.Sub(e.Date)
+			if gap < 0 {
+				gap = -gap
+			}
+			if gap > window {
+				continue
+			}
+			dateScore := 1 - float64(gap)/float64(window)
+			descScore := descriptionSimilarity(e, t)
+			if change == e.Amount {
+				// Equal amounts alone fall short of defaultMinConfidence,
+				// so that unrelated entries and transactions far apart
+				// are not paired.
+				exact = append(exact, &Match{
+					Entry:       e,
+					Transaction: t,
+					Confidence:  0.4 + 0.35*descScore + 0.25*dateScore,
+				})
+			} else if (change < 0) == (e.Amount < 0) && descScore >= mismatchSimilarity {
+				near = append(near, &Match{
+					Entry:       e,
+					Transaction: t,
+					Confidence:  0.6*descScore + 0.4*dateScore,
+					Difference:  change - e.Amount,
+				})
+			}
+		}
+	}
+	byConfidence(exact)
+	byConfidence(near)
+
+	report := new(Report)
+	entryMatch := make(map[*Entry]*Match)
+	txnMatch := make(map[*seedco.Transaction]*Match)
+	for _, m := range exact {
+		if m.Confidence < minConfidence || entryMatch[m.Entry] != nil || txnMatch[m.Transaction] != nil {
+			continue
+		}
+		entryMatch[m.Entry], txnMatch[m.Transaction] = m, m
+		report.Matched = append(report.Matched, m)
+	}
+
+	// What would have matched, had its counterpart not been taken
+	// by an equally good match or by one that it resembles, was
+	// recorded twice. Anything else is left unmatched.
+	duplicate := make(map[interface{}]bool)
+	for _, m := range exact {
+		if m.Confidence < minConfidence {
+			continue
+		}
+		em, tm := entryMatch[m.Entry], txnMatch[m.Transaction]
+		switch {
+		case em == nil && tm != nil && !duplicate[m.Entry] && isDuplicate(m, tm, entrySimilarity(m.Entry, tm.Entry)):
+			duplicate[m.Entry] = true
+			report.Duplicates = append(report.Duplicates, &Duplicate{Entry: m.Entry, Of: tm, Confidence: m.Confidence})
+		case tm == nil && em != nil && !duplicate[m.Transaction] && isDuplicate(m, em, transactionSimilarity(m.Transaction, em.Transaction)):
+			duplicate[m.Transaction] = true
+			report.Duplicates = append(report.Duplicates, &Duplicate{Transaction: m.Transaction, Of: em, Confidence: m.Confidence})
+		}
+	}
+
+	for _, m := range near {
+		if entryMatch[m.Entry] != nil || txnMatch[m.Transaction] != nil || duplicate[m.Entry] || duplicate[m.Transaction] {
+			continue
+		}
+		entryMatch[m.Entry], txnMatch[m.Transaction] = m, m
+		report.AmountMismatches = append(report.AmountMismatches, m)
+	}
+
+	for _, e := range entries {
+		if e != nil && entryMatch[e] == nil && !duplicate[e] {
+			report.UnmatchedEntries = append(report.UnmatchedEntries, e)
+		}
+	}
+	for _, t := range txns {
+		if txnMatch[t] == nil && !duplicate[t] {
+			report.UnmatchedTransactions = append(report.UnmatchedTransactions, t)
+		}
+	}
+	return report, nil
+}
+
+// isDuplicate reports whether m, whose counterpart was taken by of,
+// is as good a match as of or describes the same thing as it.
+func isDuplicate(m, of *Match, similarity float64) bool {
+	return of.Confidence-m.Confidence <= duplicateEpsilon || similarity >= mismatchSimilarity
+}
+
+// byConfidence sorts matches by decreasing confidence,
+// breaking ties by entry and transaction ID for stable output.
+func byConfidence(matches []*Match) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		mi, mj := matches[i], matches[j]
+		if mi.Confidence != mj.Confidence {
+			return mi.Confidence > mj.Confidence
+		}
+		if mi.Entry.ID != mj.Entry.ID {
+			return mi.Entry.ID < mj.Entry.ID
+		}
+		return mi.Transaction.ID < mj.Transaction.ID
+	})
+}
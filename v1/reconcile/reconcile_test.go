@@ -0,0 +1,228 @@
+package reconcile_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orijtech/seedco/v1"
+	"github.com/orijtech/seedco/v1/reconcile"
+	"github.com/orijtech/seedco/v1/seedcotest"
+)
+
+func day(d int) time.Time {
+	return time.Date(2018, time.March, d, 12, 0, 0, 0, time.UTC)
+}
+
+// txn returns a settled transaction whose amount is signed like
+// the API's, positive for money out, unlike ledger entries.
+func txn(id string, d int, amount seedco.Cents, desc string) *seedco.Transaction {
+	date := day(d)
+	return &seedco.Transaction{ID: id, Date: &date, AmountCents: amount, Description: desc, Status: seedco.Settled}
+}
+
+func TestReconcile(t *testing.T) {
+	entries := reconcile.Entries{
+		{ID: "e1", Date: day(1), Amount: -1500, Description: "Uber trip to client"},
+		{ID: "e2", Date: day(2), Amount: -120000, Description: "March rent"},
+		{ID: "e3", Date: day(5), Amount: 250000, Description: "Stripe payout"},
+		{ID: "e4", Date: day(6), Amount: -4999, Description: "AWS invoice"},
+		{ID: "e5", Date: day(20), Amount: -800, Description: "Coffee"},
+		// Recorded twice in the ledger.
+		{ID: "e6", Date: day(5), Amount: 250000, Description: "Stripe payout"},
+	}
+	transactions := []*seedco.Transaction{
+		txn("t1", 2, 1500, "UBER *TRIP 8H2K"),
+		txn("t2", 2, 120000, "ACH Landlord LLC March rent"),
+		txn("t3", 6, -250000, "STRIPE TRANSFER payout"),
+		txn("t4", 7, 5212, "AWS invoice"),
+		txn("t5", 10, 99, "Monthly fee"),
+		// The bank charged the rent twice.
+		txn("t6", 3, 120000, "ACH Landlord LLC March rent"),
+		// Too far from e5 to match it.
+		txn("t7", 28, 800, "Coffee"),
+	}
+
+	r := &reconcile.Reconciler{Ledger: entries}
+	report, err := r.ReconcileTransactions(context.Background(), transactions)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var matched []string
+	for _, m := range report.Matched {
+		matched = append(matched, m.Entry.ID+"="+m.Transaction.ID)
+		if m.Confidence < 0.5 || m.Confidence > 1 {
+			t.Errorf("%s=%s: confidence %v out of range", m.Entry.ID, m.Transaction.ID, m.Confidence)
+		}
+	}
+	sort.Strings(matched)
+	if g, w := strings.Join(matched, " "), "e1=t1 e2=t2 e3=t3"; g != w {
+		t.Errorf("matched: got=%q want=%q", g, w)
+	}
+
+	if len(report.AmountMismatches) != 1 {
+		t.Fatalf("mismatches: got=%d want=1", len(report.AmountMismatches))
+	}
+	mm := report.AmountMismatches[0]
+	if mm.Entry.ID != "e4" || mm.Transaction.ID != "t4" || mm.Difference != -213 {
+		t.Errorf("mismatch: got=(%s, %s, %v) want=(e4, t4, -213)", mm.Entry.ID, mm.Transaction.ID, mm.Difference)
+	}
+
+	var dups []string
+	for _, d := range report.Duplicates {
+		if d.Transaction != nil {
+			dups = append(dups, "txn "+d.Transaction.ID+" of "+d.Of.Transaction.ID)
+		} else {
+			dups = append(dups, "entry "+d.Entry.ID+" of "+d.Of.Entry.ID)
+		}
+	}
+	sort.Strings(dups)
+	if g, w := strings.Join(dups, " "), "entry e6 of e3 txn t6 of t2"; g != w {
+		t.Errorf("duplicates: got=%q want=%q", g, w)
+	}
+
+	if len(report.UnmatchedEntries) != 1 || report.UnmatchedEntries[0].ID != "e5" {
+		t.Errorf("unmatched entries: got=%+v want only e5", report.UnmatchedEntries)
+	}
+	var unmatched []string
+	for _, t := range report.UnmatchedTransactions {
+		unmatched = append(unmatched, t.ID)
+	}
+	if g, w := strings.Join(unmatched, " "), "t5 t7"; g != w {
+		t.Errorf("unmatched transactions: got=%q want=%q", g, w)
+	}
+}
+
+func TestReconcileUnrelatedSameAmount(t *testing.T) {
+	// The water bill costs as much as the supplies but has
+	// not cleared, so it is unmatched rather than a duplicate.
+	entries := reconcile.Entries{
+		{ID: "e1", Date: day(1), Amount: -5000, Description: "Acme Supplies"},
+		{ID: "e2", Date: day(3), Amount: -5000, Description: "City Water Utility"},
+	}
+	transactions := []*seedco.Transaction{txn("t1", 1, 5000, "ACME SUPPLIES")}
+
+	r := &reconcile.Reconciler{Ledger: entries}
+	report, err := r.ReconcileTransactions(context.Background(), transactions)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Matched) != 1 || report.Matched[0].Entry.ID != "e1" {
+		t.Errorf("matched: got=%+v want only e1=t1", report.Matched)
+	}
+	if len(report.Duplicates) != 0 {
+		t.Errorf("duplicates: got=%+v want none", report.Duplicates[0])
+	}
+	if len(report.UnmatchedEntries) != 1 || report.UnmatchedEntries[0].ID != "e2" {
+		t.Errorf("unmatched entries: got=%+v want only e2", report.UnmatchedEntries)
+	}
+}
+
+func TestReconcileUnrelatedAtWindowEdge(t *testing.T) {
+	// Equal amounts alone do not pair entries and transactions
+	// that are nothing alike and days apart.
+	entries := reconcile.Entries{{ID: "e1", Date: day(1), Amount: -5000, Description: "Office chairs"}}
+	transactions := []*seedco.Transaction{txn("t1", 4, 5000, "ZQX 8812")}
+
+	r := &reconcile.Reconciler{Ledger: entries}
+	report, err := r.ReconcileTransactions(context.Background(), transactions)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Matched) != 0 || len(report.Duplicates) != 0 {
+		t.Errorf("got %d matched and %d duplicates, want none", len(report.Matched), len(report.Duplicates))
+	}
+	if len(report.UnmatchedEntries) != 1 || len(report.UnmatchedTransactions) != 1 {
+		t.Errorf("got %d unmatched entries and %d unmatched transactions, want 1 each",
+			len(report.UnmatchedEntries), len(report.UnmatchedTransactions))
+	}
+}
+
+func TestReconcileConfidence(t *testing.T) {
+	entries := reconcile.Entries{{ID: "e1", Date: day(1), Amount: -1500, Description: "Uber"}}
+	tests := [...]struct {
+		txn         *seedco.Transaction
+		wantMatched bool
+	}{
+		0: {txn: txn("t1", 1, 1500, "Uber")},
+		1: {txn: txn("t1", 2, 1500, "Lyft")},
+		2: {txn: txn("t1", 5, 1500, "Uber")},
+	}
+
+	var confidences []float64
+	for i, tt := range tests {
+		r := &reconcile.Reconciler{Ledger: entries}
+		report, err := r.ReconcileTransactions(context.Background(), []*seedco.Transaction{tt.txn})
+		if err != nil {
+			t.Fatalf("#%d: unexpected error: %v", i, err)
+		}
+		if len(report.Matched) == 0 {
+			confidences = append(confidences, 0)
+			continue
+		}
+		confidences = append(confidences, report.Matched[0].Confidence)
+	}
+	// An exact match beats a distant, differently described
+	// one, while one outside the date window does not match.
+	if confidences[0] != 1 {
+		t.Errorf("exact match: got=%v want=1", confidences[0])
+	}
+	if !(confidences[1] > 0 && confidences[1] < confidences[0]) {
+		t.Errorf("loose match: got=%v want between 0 and %v", confidences[1], confidences[0])
+	}
+	if confidences[2] != 0 {
+		t.Errorf("outside the window: got=%v want no match", confidences[2])
+	}
+}
+
+type failingLedger struct{}
+
+var errLedgerDown = errors.New("ledger down")
+
+func (failingLedger) Entries(ctx context.Context) ([]*reconcile.Entry, error) {
+	return nil, errLedgerDown
+}
+
+func TestReconcileIterator(t *testing.T) {
+	srv := seedcotest.NewUnstartedServer()
+	pending := txn("t2", 2, 700, "Uber")
+	pending.Status = seedco.Pending
+	srv.AddTransactions(txn("t1", 1, 1500, "Uber"), pending)
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	entries := reconcile.Entries{
+		{ID: "e1", Date: day(1), Amount: -1500, Description: "Uber"},
+		{ID: "e2", Date: day(2), Amount: -700, Description: "Uber"},
+	}
+
+	r := &reconcile.Reconciler{Ledger: entries}
+	report, err := r.Reconcile(ctx, client.Transactions(nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Matched) != 1 || len(report.UnmatchedEntries) != 1 || len(report.UnmatchedTransactions) != 0 {
+		t.Errorf("settled only: got %d matched, %d unmatched entries and %d unmatched transactions",
+			len(report.Matched), len(report.UnmatchedEntries), len(report.UnmatchedTransactions))
+	}
+
+	r.IncludePending = true
+	report, err = r.Reconcile(ctx, client.Transactions(nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Matched) != 2 {
+		t.Errorf("with pending: got %d matched want 2", len(report.Matched))
+	}
+
+	r.Ledger = failingLedger{}
+	if _, err := r.Reconcile(ctx, client.Transactions(nil)); err != errLedgerDown {
+		t.Errorf("failing ledger: got=(%v) want=(%v)", err, errLedgerDown)
+	}
+}
@@ -0,0 +1,228 @@
+package seedco
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how a Client retries requests that failed
+// transiently. A Client without a RetryPolicy never retries.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts
+	// including the first one. Values below 2 disable retries.
+	MaxAttempts int
+
+	// InitialBackoff is the wait before the first retry; every
+	// later wait is Multiplier times the previous, up to MaxBackoff.
+	// A Retry-After from the server longer than MaxBackoff ends the
+	// retries instead of blocking the call until then.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+
+	// Jitter, between 0 and 1, is the fraction of each
+	// backoff that is randomized to avoid thundering herds.
+	Jitter float64
+
+	// RetryableStatusCodes are the HTTP status codes worth retrying.
+	// Connection level errors such as resets are always retryable.
+	RetryableStatusCodes []int
+
+	// RetryNonIdempotent allows retrying requests other than GET, HEAD,
+	// OPTIONS, PUT and DELETE that do not carry an Idempotency-Key.
+	RetryNonIdempotent bool
+
+	// ShouldRetry, if set, overrides the decision of whether the
+	// failed attempt with the given number and error is retried.
+	ShouldRetry func(attempt int, err error) bool
+
+	// OnAttempt, if set, is invoked after every attempt.
+	OnAttempt func(*RetryAttempt)
+}
+
+// RetryAttempt describes the outcome of a single attempt.
+type RetryAttempt struct {
+	// Attempt is 1 for the first attempt.
+	Attempt int
+	Request *http.Request
+	Err     error
+
+	// StatusCode is 0 if no response was received
+	// or if the attempt succeeded.
+	StatusCode int
+
+	// Retry reports whether another attempt will be made after Backoff.
+	Retry   bool
+	Backoff time.Duration
+}
+
+// DefaultRetryPolicy returns a policy that makes up to 4 attempts,
+// backing off exponentially from 250ms up to 10s on connection
+// errors, 429 and 502, 503 and 504 responses.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 250 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// SetRetryPolicy makes the client retry transient failures
+// according to rp. A nil rp disables retries.
+func (c *Client) SetRetryPolicy(rp *RetryPolicy) {
+	c.mu.Lock()
+	c.rp = rp
+	c.mu.Unlock()
+}
+
+func (c *Client) retryPolicy() *RetryPolicy {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.rp
+}
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+func (rp *RetryPolicy) appliesTo(req *http.Request) bool {
+	if rp.MaxAttempts < 2 {
+		return false
+	}
+	if rp.RetryNonIdempotent || req.Header.Get(idempotencyKeyHeader) != "" {
+		return true
+	}
+	switch req.Method {
+	case "", "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		return true
+	default:
+		return false
+	}
+}
+
+func (rp *RetryPolicy) shouldRetry(attempt int, err error) bool {
+	if err == nil || attempt >= rp.MaxAttempts {
+		return false
+	}
+	if rp.ShouldRetry != nil {
+		return rp.ShouldRetry(attempt, err)
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var ae *APIError
+	if !errors.As(err, &ae) {
+		// A failure to get any response at all, such as a reset connection.
+		return true
+	}
+	for _, code := range rp.RetryableStatusCodes {
+		if ae.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns how long to wait after the given failed attempt,
+// preferring the server's Retry-After header if it sent one. It
+// reports false if that is longer than MaxBackoff.
+func (rp *RetryPolicy) backoff(attempt int, err error) (time.Duration, bool) {
+	var ae *APIError
+	if errors.As(err, &ae) {
+		if d, ok := parseRetryAfter(ae.Header.Get("Retry-After"), time.Now()); ok {
+			return d, rp.MaxBackoff <= 0 || d <= rp.MaxBackoff
+		}
+	}
+	multiplier := rp.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	d := float64(rp.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if rp.MaxBackoff > 0 && d > float64(rp.MaxBackoff) {
+		d = float64(rp.MaxBackoff)
+	}
+	if jitter := rp.Jitter; jitter > 0 {
+		if jitter > 1 {
+			jitter = 1
+		}
+		d += d * jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(d), true
+}
+
+// parseRetryAfter parses the value of a Retry-After header
+// which is either a number of seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	at, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if d := at.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
+
+func statusCodeOf(err error) int {
+	var ae *APIError
+	if errors.As(err, &ae) {
+		return ae.StatusCode
+	}
+	return 0
+}
+
+// doReqWithRetries sends req, retrying it as rp allows.
+func (c *Client) doReqWithRetries(ctx context.Context, req *http.Request, rp *RetryPolicy) ([]byte, *http.Response, error) {
+	retryable := rp.appliesTo(req)
+	attemptReq := req
+	for attempt := 1; ; attempt++ {
+		blob, res, err := c.doReqOnce(ctx, attemptReq)
+		ra := &RetryAttempt{
+			Attempt:    attempt,
+			Request:    attemptReq,
+			Err:        err,
+			StatusCode: statusCodeOf(err),
+		}
+		var nextReq *http.Request
+		if retryable && rp.shouldRetry(attempt, err) {
+			if nextReq, _ = rewindRequest(ctx, req); nextReq != nil {
+				ra.Backoff, ra.Retry = rp.backoff(attempt, err)
+			}
+		}
+		if rp.OnAttempt != nil {
+			rp.OnAttempt(ra)
+		}
+		if !ra.Retry {
+			return blob, res, err
+		}
+
+		timer := time.NewTimer(ra.Backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return blob, res, err
+		}
+		attemptReq = nextReq
+	}
+}
@@ -0,0 +1,172 @@
+package seedco_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/orijtech/seedco/v1"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	tests := [...]struct {
+		failures     []int
+		maxAttempts  int
+		wantAttempts int
+		wantErr      bool
+	}{
+		0: {failures: nil, maxAttempts: 3, wantAttempts: 1},
+		1: {failures: []int{503, 502}, maxAttempts: 3, wantAttempts: 3},
+		2: {failures: []int{503, 502, 504}, maxAttempts: 3, wantAttempts: 3, wantErr: true},
+		3: {failures: []int{400}, maxAttempts: 3, wantAttempts: 1, wantErr: true},
+		4: {failures: []int{429}, maxAttempts: 1, wantAttempts: 1, wantErr: true},
+		5: {failures: []int{0, 0}, maxAttempts: 3, wantAttempts: 3},
+	}
+
+	for i, tt := range tests {
+		client, err := seedco.NewClientWithToken(token1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fb := &flakyBackend{failures: tt.failures}
+		client.SetHTTPRoundTripper(fb)
+
+		var observed []*seedco.RetryAttempt
+		rp := seedco.DefaultRetryPolicy()
+		rp.MaxAttempts = tt.maxAttempts
+		rp.InitialBackoff = time.Millisecond
+		rp.OnAttempt = func(ra *seedco.RetryAttempt) {
+			observed = append(observed, ra)
+		}
+		client.SetRetryPolicy(rp)
+
+		_, err = client.ListBalances()
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: want non-nil error", i)
+			}
+		} else if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+		}
+		if g, w := fb.attemptCount(), tt.wantAttempts; g != w {
+			t.Errorf("#%d: attempts: got=%d want=%d", i, g, w)
+		}
+		if g, w := len(observed), fb.attemptCount(); g != w {
+			t.Errorf("#%d: observed attempts: got=%d want=%d", i, g, w)
+		}
+		if n := len(observed); n > 0 && observed[n-1].Retry {
+			t.Errorf("#%d: last attempt unexpectedly marked for retry", i)
+		}
+	}
+}
+
+func TestRetryPolicyHonorsRetryAfter(t *testing.T) {
+	client, err := seedco.NewClientWithToken(token1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetHTTPRoundTripper(&flakyBackend{failures: []int{429}, retryAfter: "7"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var backoff time.Duration
+	rp := seedco.DefaultRetryPolicy()
+	rp.OnAttempt = func(ra *seedco.RetryAttempt) {
+		backoff = ra.Backoff
+		// Give up instead of actually waiting out the Retry-After.
+		cancel()
+	}
+	client.SetRetryPolicy(rp)
+
+	_, err = client.ListBalancesWithContext(ctx)
+	if !errors.Is(err, seedco.ErrRateLimited) {
+		t.Errorf("got=(%v) want=(%v)", err, seedco.ErrRateLimited)
+	}
+	if g, w := backoff, 7*time.Second; g != w {
+		t.Errorf("backoff: got=%v want=%v", g, w)
+	}
+}
+
+func TestRetryPolicyGivesUpOnLongRetryAfter(t *testing.T) {
+	client, err := seedco.NewClientWithToken(token1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetHTTPRoundTripper(&flakyBackend{failures: []int{429}, retryAfter: "86400"})
+	client.SetRateLimiter(seedco.Unlimited)
+	var attempts []*seedco.RetryAttempt
+	rp := seedco.DefaultRetryPolicy()
+	rp.OnAttempt = func(ra *seedco.RetryAttempt) { attempts = append(attempts, ra) }
+	client.SetRetryPolicy(rp)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := client.ListBalancesWithContext(ctx); !errors.Is(err, seedco.ErrRateLimited) {
+		t.Errorf("got=(%v) want=(%v)", err, seedco.ErrRateLimited)
+	}
+	if len(attempts) != 1 || attempts[0].Retry {
+		t.Errorf("attempts: got %d, retry=%v want a single attempt that is not retried", len(attempts), len(attempts) > 0 && attempts[0].Retry)
+	}
+}
+
+func TestRetryPolicySkipsNonIdempotentRequests(t *testing.T) {
+	client, err := seedco.NewClientWithToken("token2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fb := &flakyBackend{failures: []int{503}}
+	client.SetHTTPRoundTripper(fb)
+	rp := seedco.DefaultRetryPolicy()
+	rp.InitialBackoff = time.Millisecond
+	client.SetRetryPolicy(rp)
+
+	// AuthToken is a POST without an Idempotency-Key.
+	if _, err := client.AuthToken("username", "password"); err == nil {
+		t.Fatal("want non-nil error")
+	}
+	if g, w := fb.attemptCount(), 1; g != w {
+		t.Errorf("attempts: got=%d want=%d", g, w)
+	}
+}
+
+// flakyBackend fails with the status codes in failures, in order,
+// before serving balances. A 0 status code is a connection error.
+type flakyBackend struct {
+	mu         sync.Mutex
+	failures   []int
+	attempts   int
+	retryAfter string
+}
+
+var _ http.RoundTripper = (*flakyBackend)(nil)
+
+var errConnReset = errors.New("connection reset by peer")
+
+func (fb *flakyBackend) attemptCount() int {
+	fb.mu.Lock()
+	defer fb.mu.Unlock()
+	return fb.attempts
+}
+
+func (fb *flakyBackend) RoundTrip(req *http.Request) (*http.Response, error) {
+	fb.mu.Lock()
+	defer fb.mu.Unlock()
+	fb.attempts += 1
+	if len(fb.failures) == 0 {
+		return respFromFile("./testdata/bank-acct1.json")
+	}
+	code := fb.failures[0]
+	fb.failures = fb.failures[1:]
+	if code == 0 {
+		return nil, errConnReset
+	}
+	res, err := makeResp(http.StatusText(code), code, nil)
+	if fb.retryAfter != "" {
+		res.Header.Set("Retry-After", fb.retryAfter)
+	}
+	return res, err
+}
@@ -1,9 +1,12 @@
 package seedco
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
 	"sync"
@@ -11,37 +14,188 @@ import (
 	"github.com/orijtech/otils"
 )
 
-const baseURL = "https://api.seed.co/v1"
+const (
+	// ProductionBaseURL is the base URL of Seed's live API.
+	ProductionBaseURL = "https://api.seed.co/v1"
+
+	// SandboxBaseURL is the base URL of Seed's sandbox API
+	// whose accounts and transactions are not real.
+	SandboxBaseURL = "https://sandbox.seed.co/v1"
+)
 
 type Client struct {
 	rt http.RoundTripper
 	mu sync.RWMutex
 
+	// _baseURL, if set, replaces ProductionBaseURL.
+	_baseURL string
+
 	_authToken string
+
+	// ts, if set, takes precedence over _authToken.
+	ts TokenSource
+
+	rp *RetryPolicy
+	rl RateLimiter
+
+	batchConcurrency int
+
+	onBalanceWarning func(*BalanceWarning)
+}
+
+// doAuthAndReq sends the authorized req and returns the body of its
+// response, which has already been read and closed. Mutating requests
+// are given an Idempotency-Key, which also makes them retryable.
+func (c *Client) doAuthAndReq(ctx context.Context, req *http.Request) ([]byte, *http.Response, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := setIdempotencyKey(ctx, req); err != nil {
+		return nil, nil, err
+	}
+	var blob []byte
+	var res *http.Response
+	err := c.withAuth(ctx, req, func(authdReq *http.Request) (err error) {
+		blob, res, err = c.doReq(ctx, authdReq)
+		return err
+	})
+	return blob, res, err
+}
+
+// doAuthAndStream is like doAuthAndReq but returns the response of
+// a successful request with its body unread. It never retries since
+// the caller may already have consumed part of the body.
+func (c *Client) doAuthAndStream(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var res *http.Response
+	err := c.withAuth(ctx, req, func(authdReq *http.Request) (err error) {
+		res, err = c.sendReq(ctx, authdReq)
+		return err
+	})
+	return res, err
+}
+
+// withAuth passes req, authorized with the current access token, to send.
+// If the token is rejected it is refreshed and send is invoked once more.
+func (c *Client) withAuth(ctx context.Context, req *http.Request, send func(*http.Request) error) error {
+	ts := c.tokenSource()
+	accessToken, err := c.accessToken(ctx, ts)
+	if err != nil {
+		return err
+	}
+	err = send(withBearerToken(req, accessToken))
+	refresher, ok := ts.(tokenRefresher)
+	if !errors.Is(err, ErrUnauthorized) || !ok {
+		return err
+	}
+
+	// The token was rejected, so refresh it and try exactly once more.
+	retryReq, rerr := rewindRequest(ctx, req)
+	if rerr != nil {
+		return err
+	}
+	if rerr := refresher.refreshStale(ctx, accessToken); rerr != nil {
+		return err
+	}
+	if accessToken, rerr = c.accessToken(ctx, ts); rerr != nil {
+		return err
+	}
+	return send(withBearerToken(retryReq, accessToken))
+}
+
+func (c *Client) accessToken(ctx context.Context, ts TokenSource) (string, error) {
+	if ts == nil {
+		return c.authToken(), nil
+	}
+	var tok *Token
+	var err error
+	if cts, ok := ts.(contextTokenSource); ok {
+		tok, err = cts.tokenWithContext(ctx)
+	} else {
+		tok, err = ts.Token()
+	}
+	if err != nil {
+		return "", err
+	}
+	if tok == nil {
+		return "", errNilToken
+	}
+	return tok.AccessToken, nil
 }
 
-func (c *Client) doAuthAndReq(req *http.Request) ([]byte, http.Header, error) {
-	bearerToken := fmt.Sprintf("Bearer %s", c.authToken())
+func withBearerToken(req *http.Request, token string) *http.Request {
+	bearerToken := fmt.Sprintf("Bearer %s", token)
 	req.Header.Set("Authorization", bearerToken)
-	return c.doReq(req)
+	return req
 }
 
-func (c *Client) doReq(req *http.Request) ([]byte, http.Header, error) {
-	res, err := c.httpClient().Do(req)
+// rewindRequest returns a copy of req whose body,
+// if any, can be sent again from the start.
+func rewindRequest(ctx context.Context, req *http.Request) (*http.Request, error) {
+	clone := req.Clone(ctx)
+	if req.Body == nil || req.Body == http.NoBody {
+		return clone, nil
+	}
+	if req.GetBody == nil {
+		return nil, errUnrewindableBody
+	}
+	body, err := req.GetBody()
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
-	if res.Body != nil {
-		defer res.Body.Close()
+	clone.Body = body
+	return clone, nil
+}
+
+func (c *Client) doReq(ctx context.Context, req *http.Request) ([]byte, *http.Response, error) {
+	if ctx == nil {
+		ctx = context.Background()
 	}
-	if !otils.StatusOK(res.StatusCode) {
-		return nil, res.Header, fmt.Errorf(res.Status)
+	if rp := c.retryPolicy(); rp != nil {
+		return c.doReqWithRetries(ctx, req, rp)
 	}
+	return c.doReqOnce(ctx, req)
+}
+
+func (c *Client) doReqOnce(ctx context.Context, req *http.Request) ([]byte, *http.Response, error) {
+	res, err := c.sendReq(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
 	blob, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return nil, res.Header, err
+		return nil, res, err
+	}
+	return blob, res, nil
+}
+
+// sendReq sends req once, subject to the rate limiter. It returns
+// the response of a 2XX status with its body unread and an *APIError
+// for any other status.
+func (c *Client) sendReq(ctx context.Context, req *http.Request) (*http.Response, error) {
+	rl := c.rateLimiter()
+	if err := rl.Wait(ctx); err != nil {
+		return nil, err
+	}
+	res, err := c.httpClient().Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if ro, ok := rl.(ResponseObserver); ok {
+		ro.ObserveResponse(res.StatusCode, res.Header)
 	}
-	return blob, res.Header, nil
+	if res.Body == nil {
+		res.Body = http.NoBody
+	}
+	if !otils.StatusOK(res.StatusCode) {
+		blob, _ := ioutil.ReadAll(res.Body)
+		_ = res.Body.Close()
+		return nil, newAPIError(res, blob)
+	}
+	return res, nil
 }
 
 func (c *Client) authToken() string {
@@ -50,16 +204,35 @@ func (c *Client) authToken() string {
 	return c._authToken
 }
 
-const EnvBearerTokenKey = "SEEDCO_BEARER_TOKEN"
+const (
+	EnvBearerTokenKey = "SEEDCO_BEARER_TOKEN"
+
+	// EnvBaseURLKey optionally names the base URL, or one of the
+	// presets "production" and "sandbox", that NewClientFromEnv uses.
+	EnvBaseURLKey = "SEEDCO_BASE_URL"
+)
 
-var errMissingBearerTokenFromEnv = fmt.Errorf("missing %q value from environment", EnvBearerTokenKey)
+var (
+	errMissingBearerTokenFromEnv = fmt.Errorf("missing %q value from environment", EnvBearerTokenKey)
+	errInvalidBaseURL            = errors.New("base URL must be an absolute http or https URL")
+	errUnrewindableBody          = errors.New("request body cannot be rewound")
+)
 
 func NewClientFromEnv() (*Client, error) {
 	token := strings.TrimSpace(os.Getenv(EnvBearerTokenKey))
 	if token == "" {
 		return nil, errMissingBearerTokenFromEnv
 	}
-	return NewClientWithToken(token)
+	c, err := NewClientWithToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if baseURL := strings.TrimSpace(os.Getenv(EnvBaseURLKey)); baseURL != "" {
+		if err := c.SetBaseURL(baseURL); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
 }
 
 func NewClientWithToken(token string) (*Client, error) {
@@ -73,20 +246,60 @@ func (c *Client) httpClient() *http.Client {
 	return &http.Client{Transport: rt}
 }
 
+// SetBaseURL points the client at another Seed API server, for
+// example SandboxBaseURL or a local fake. The presets "production"
+// and "sandbox" are accepted in place of their URLs.
+func (c *Client) SetBaseURL(baseURL string) error {
+	switch strings.ToLower(baseURL) {
+	case "production":
+		baseURL = ProductionBaseURL
+	case "sandbox":
+		baseURL = SandboxBaseURL
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errInvalidBaseURL
+	}
+	c.mu.Lock()
+	c._baseURL = strings.TrimRight(baseURL, "/")
+	c.mu.Unlock()
+	return nil
+}
+
+// BaseURL returns the base URL that requests are sent to.
+func (c *Client) BaseURL() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c._baseURL == "" {
+		return ProductionBaseURL
+	}
+	return c._baseURL
+}
+
 func (c *Client) SetHTTPRoundTripper(rt http.RoundTripper) {
 	c.mu.Lock()
 	c.rt = rt
 	c.mu.Unlock()
 }
 
+// SetAuthToken sets a bare access token. The token
+// is used as is and never refreshed by the client;
+// use SetToken to have the client manage its lifecycle.
 func (c *Client) SetAuthToken(token string) {
 	c.mu.Lock()
 	c._authToken = token
+	c.ts = nil
 	c.mu.Unlock()
 }
 
 type Error struct {
 	Message string `json:"message"`
+
+	// Field, if set, is the request field that the error pertains to.
+	Field string `json:"field,omitempty"`
 }
 
 var _ error = (*Error)(nil)
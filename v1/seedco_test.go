@@ -0,0 +1,81 @@
+package seedco_test
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/orijtech/seedco/v1"
+)
+
+func TestSetBaseURL(t *testing.T) {
+	tests := [...]struct {
+		baseURL string
+		wantErr bool
+		wantURL string
+	}{
+		0: {"", true, ""},
+		1: {"ftp://example.org/v1", true, ""},
+		2: {"/v1", true, ""},
+		3: {"sandbox", false, seedco.SandboxBaseURL + "/public/balance"},
+		4: {"production", false, seedco.ProductionBaseURL + "/public/balance"},
+		5: {"http://localhost:8080/v1/", false, "http://localhost:8080/v1/public/balance"},
+	}
+
+	for i, tt := range tests {
+		client, err := seedco.NewClientWithToken(token1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = client.SetBaseURL(tt.baseURL)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("#%d: want non-nil error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+			continue
+		}
+		rec := &urlRecorder{RoundTripper: &backend{route: listBalancesRoute}}
+		client.SetHTTPRoundTripper(rec)
+		if _, err := client.ListBalances(); err != nil {
+			t.Errorf("#%d: unexpected error: %v", i, err)
+			continue
+		}
+		if g, w := rec.lastURL, tt.wantURL; g != w {
+			t.Errorf("#%d: url: got=%q want=%q", i, g, w)
+		}
+	}
+}
+
+func TestNewClientFromEnvBaseURL(t *testing.T) {
+	defer os.Setenv(seedco.EnvBearerTokenKey, os.Getenv(seedco.EnvBearerTokenKey))
+	defer os.Setenv(seedco.EnvBaseURLKey, os.Getenv(seedco.EnvBaseURLKey))
+
+	os.Setenv(seedco.EnvBearerTokenKey, token1)
+	os.Setenv(seedco.EnvBaseURLKey, "sandbox")
+	client, err := seedco.NewClientFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, w := client.BaseURL(), seedco.SandboxBaseURL; g != w {
+		t.Errorf("baseURL: got=%q want=%q", g, w)
+	}
+
+	os.Setenv(seedco.EnvBaseURLKey, "not a url")
+	if _, err := seedco.NewClientFromEnv(); err == nil {
+		t.Errorf("invalid %s: want non-nil error", seedco.EnvBaseURLKey)
+	}
+}
+
+type urlRecorder struct {
+	http.RoundTripper
+	lastURL string
+}
+
+func (ur *urlRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	ur.lastURL = req.URL.String()
+	return ur.RoundTripper.RoundTrip(req)
+}
@@ -0,0 +1,1146 @@
+// Package seedcotest provides an in-memory fake of the Seed API
+// so that code built on package seedco can be tested without
+// network access.
+//
+// A fake is either served over HTTP with NewServer, or used
+// in-process as an http.RoundTripper through Server.Transport:
+//
+//	srv := seedcotest.NewServer()
+//	defer srv.Close()
+//	srv.AddTransactions(transactions...)
+//	client, err := srv.Client()
+package seedcotest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/orijtech/seedco/v1"
+)
+
+// Routes of the fake, relative to its base URL, that errors can be
+// injected into with FailNext. The routes of the individual resources
+// below a collection route, such as a single transaction, share the
+// collection route's injected failures and request count.
+const (
+	AuthRoute             = "/public/auth/token"
+	RefreshRoute          = "/public/auth/token/refresh"
+	BalanceRoute          = "/public/balance"
+	TransactionsRoute     = "/public/transactions"
+	APIVersionRoute       = "/public/api/client-version"
+	CheckingAccountsRoute = "/public/checking_accounts"
+	PaymentsRoute         = "/public/payments"
+	PayeesRoute           = "/public/payees"
+)
+
+var collectionRoutes = []string{
+	CheckingAccountsRoute,
+	PayeesRoute,
+	PaymentsRoute,
+	TransactionsRoute,
+}
+
+// routeFamily returns the collection route that route belongs to.
+func routeFamily(route string) string {
+	for _, prefix := range collectionRoutes {
+		if strings.HasPrefix(route, prefix+"/") {
+			return prefix
+		}
+	}
+	return route
+}
+
+// basePath is the path prefix that the fake serves under,
+// mirroring the "/v1" of seedco.ProductionBaseURL.
+const basePath = "/v1"
+
+// TokenLifetime is the expires_in of the tokens that the fake issues.
+const TokenLifetime = time.Hour
+
+type Server struct {
+	// URL is the base URL to pass to seedco.Client.SetBaseURL.
+	// It is blank for fakes created with NewUnstartedServer.
+	URL string
+
+	ts *httptest.Server
+
+	mu            sync.Mutex
+	passwords     map[string]string
+	accessTokens  map[string]time.Time
+	refreshTokens map[string]bool
+	balances      []*seedco.Balance
+	accounts      []*seedco.CheckingAccount
+	transactions  []*seedco.Transaction
+	attachments   map[string][]*storedAttachment
+	payments      []*seedco.Payment
+	paymentKeys   map[string]*seedco.Payment
+	payees        []*seedco.Payee
+	apiVersion    *seedco.APIVersion
+	failures      map[string][]*failure
+	requests      map[string]int
+}
+
+type failure struct {
+	code int
+	hdr  http.Header
+	msgs []string
+}
+
+// NewServer starts a fake Seed API server listening on a local port.
+// It must be closed with Close.
+func NewServer() *Server {
+	s := NewUnstartedServer()
+	s.ts = httptest.NewServer(s)
+	s.URL = s.ts.URL + basePath
+	return s
+}
+
+// NewUnstartedServer returns a fake that does not listen on
+// any port and can only be reached through Transport.
+func NewUnstartedServer() *Server {
+	return &Server{
+		passwords:     make(map[string]string),
+		accessTokens:  make(map[string]time.Time),
+		refreshTokens: make(map[string]bool),
+		attachments:   make(map[string][]*storedAttachment),
+		paymentKeys:   make(map[string]*seedco.Payment),
+		failures:      make(map[string][]*failure),
+		requests:      make(map[string]int),
+		apiVersion:    &seedco.APIVersion{Version: "2", ID: "seedcotest"},
+	}
+}
+
+// Close shuts down the server started by NewServer.
+func (s *Server) Close() {
+	if s.ts != nil {
+		s.ts.Close()
+	}
+}
+
+// Client returns a seedco.Client that talks to the fake and
+// is authenticated with a freshly issued, refreshable token.
+func (s *Server) Client() (*seedco.Client, error) {
+	client, err := seedco.NewClientFromToken(s.IssueToken())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.configure(client); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+func (s *Server) configure(client *seedco.Client) error {
+	client.SetRateLimiter(seedco.Unlimited)
+	if s.ts == nil {
+		client.SetHTTPRoundTripper(s.Transport())
+		return client.SetBaseURL(seedco.ProductionBaseURL)
+	}
+	return client.SetBaseURL(s.URL)
+}
+
+// Transport returns an http.RoundTripper that serves every
+// request in-process regardless of the request's host.
+func (s *Server) Transport() http.RoundTripper {
+	return roundTripper{s}
+}
+
+type roundTripper struct {
+	s *Server
+}
+
+func (rt roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	rec := httptest.NewRecorder()
+	rt.s.ServeHTTP(rec, req)
+	res := rec.Result()
+	res.Request = req
+	return res, nil
+}
+
+// AddUser registers credentials that AuthToken accepts.
+func (s *Server) AddUser(username, password string) {
+	s.mu.Lock()
+	s.passwords[username] = password
+	s.mu.Unlock()
+}
+
+// IssueToken issues a valid token without going through AuthToken.
+func (s *Server) IssueToken() *seedco.Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.issueTokenLocked()
+}
+
+func (s *Server) issueTokenLocked() *seedco.Token {
+	tok := &seedco.Token{
+		AccessToken:  "2.a." + randomID(),
+		RefreshToken: "2.r." + randomID(),
+		ExpiresIn:    TokenLifetime.Seconds(),
+		Permissions:  "public-api",
+		TokenType:    "Bearer",
+	}
+	s.accessTokens[tok.AccessToken] = time.Now().Add(TokenLifetime)
+	s.refreshTokens[tok.RefreshToken] = true
+	return tok
+}
+
+// ExpireToken makes every later request with accessToken fail with 401.
+func (s *Server) ExpireToken(accessToken string) {
+	s.mu.Lock()
+	delete(s.accessTokens, accessToken)
+	s.mu.Unlock()
+}
+
+// SetBalances replaces the balances that the fake reports.
+func (s *Server) SetBalances(balances ...*seedco.Balance) {
+	s.mu.Lock()
+	s.balances = append([]*seedco.Balance(nil), balances...)
+	s.mu.Unlock()
+}
+
+// AddCheckingAccounts adds accounts, replacing those with the same ID.
+// Accounts without an ID are assigned one.
+func (s *Server) AddCheckingAccounts(accounts ...*seedco.CheckingAccount) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, acct := range accounts {
+		if acct.ID == "" {
+			acct.ID = randomID()
+		}
+		replaced := false
+		for i, cur := range s.accounts {
+			if cur.ID == acct.ID {
+				s.accounts[i] = acct
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			s.accounts = append(s.accounts, acct)
+		}
+	}
+}
+
+// AddTransactions adds transactions, replacing those with the same ID.
+// Transactions without an ID are assigned one.
+func (s *Server) AddTransactions(transactions ...*seedco.Transaction) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range transactions {
+		if t.ID == "" {
+			t.ID = randomID()
+		}
+		replaced := false
+		for i, cur := range s.transactions {
+			if cur.ID == t.ID {
+				s.transactions[i] = t
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			s.transactions = append(s.transactions, t)
+		}
+	}
+	// List the newest transactions first. The API does not define
+	// an order, so clients must not rely on this one.
+	sort.SliceStable(s.transactions, func(i, j int) bool {
+		return dateOf(s.transactions[i]).After(dateOf(s.transactions[j]))
+	})
+}
+
+// RemoveTransaction deletes the transaction with the given ID.
+func (s *Server) RemoveTransaction(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, t := range s.transactions {
+		if t.ID == id {
+			s.transactions = append(s.transactions[:i], s.transactions[i+1:]...)
+			return
+		}
+	}
+}
+
+// AddPayments adds payments, replacing those with the same ID.
+// Payments without an ID are assigned one.
+func (s *Server) AddPayments(payments ...*seedco.Payment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range payments {
+		if p.ID == "" {
+			p.ID = randomID()
+		}
+		if s.paymentLocked(p.ID) != nil {
+			s.replacePaymentLocked(p)
+		} else {
+			s.payments = append(s.payments, p)
+		}
+	}
+}
+
+// Payments returns every payment that the fake knows
+// of, including those created through the API.
+func (s *Server) Payments() []*seedco.Payment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*seedco.Payment(nil), s.payments...)
+}
+
+// AddPayees adds payees, replacing those with the same ID.
+// Payees without an ID are assigned one.
+func (s *Server) AddPayees(payees ...*seedco.Payee) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range payees {
+		if p.ID == "" {
+			p.ID = randomID()
+		}
+		if i := s.payeeIndexLocked(p.ID); i >= 0 {
+			s.payees[i] = p
+		} else {
+			s.payees = append(s.payees, p)
+		}
+	}
+}
+
+// SetAPIVersion replaces the version that the fake reports.
+func (s *Server) SetAPIVersion(v *seedco.APIVersion) {
+	s.mu.Lock()
+	s.apiVersion = v
+	s.mu.Unlock()
+}
+
+// FailNext makes the next request to route fail with the given status
+// code and error messages. Calls queue up: n calls fail n requests.
+func (s *Server) FailNext(route string, code int, msgs ...string) {
+	s.FailNextWithHeader(route, code, nil, msgs...)
+}
+
+// FailNextWithHeader is like FailNext but also sets hdr on
+// the failed response, for example a Retry-After.
+func (s *Server) FailNextWithHeader(route string, code int, hdr http.Header, msgs ...string) {
+	s.mu.Lock()
+	s.failures[route] = append(s.failures[route], &failure{code: code, hdr: hdr, msgs: msgs})
+	s.mu.Unlock()
+}
+
+// Requests returns how many requests were made to route,
+// including those that failed.
+func (s *Server) Requests(route string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[route]
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	route := strings.TrimPrefix(req.URL.Path, basePath)
+
+	failureRoute := routeFamily(route)
+
+	s.mu.Lock()
+	s.requests[failureRoute] += 1
+	var injected *failure
+	if queue := s.failures[failureRoute]; len(queue) > 0 {
+		injected, s.failures[failureRoute] = queue[0], queue[1:]
+	}
+	s.mu.Unlock()
+
+	if injected != nil {
+		for key, values := range injected.hdr {
+			w.Header()[key] = values
+		}
+		writeErrors(w, injected.code, injected.msgs...)
+		return
+	}
+
+	switch route {
+	case AuthRoute:
+		s.handleAuth(w, req)
+	case RefreshRoute:
+		s.handleRefresh(w, req)
+	default:
+		if !s.authorized(req) {
+			writeErrors(w, http.StatusUnauthorized, "Unauthorized.")
+			return
+		}
+		s.serveAuthorized(w, req, route)
+	}
+}
+
+func (s *Server) serveAuthorized(w http.ResponseWriter, req *http.Request, route string) {
+	switch route {
+	case BalanceRoute:
+		s.handleBalances(w, req)
+	case TransactionsRoute:
+		s.handleTransactions(w, req)
+	case APIVersionRoute:
+		s.handleAPIVersion(w, req)
+	case CheckingAccountsRoute:
+		s.handleCheckingAccounts(w, req)
+	case PaymentsRoute:
+		s.handlePayments(w, req)
+	case PayeesRoute:
+		s.handlePayees(w, req)
+	default:
+		if id, ok := subroute(route, CheckingAccountsRoute); ok {
+			s.handleCheckingAccount(w, req, id)
+			return
+		}
+		if parts, ok := subroutes(route, TransactionsRoute); ok {
+			s.serveTransactionRoute(w, req, parts)
+			return
+		}
+		if parts, ok := subroutes(route, PaymentsRoute); ok {
+			s.servePaymentRoute(w, req, parts)
+			return
+		}
+		if id, ok := subroute(route, PayeesRoute); ok {
+			s.handlePayee(w, req, id)
+			return
+		}
+		writeErrors(w, http.StatusNotFound, "Not Found.")
+	}
+}
+
+// subroute returns the ID in route if it is of the form prefix/ID.
+func subroute(route, prefix string) (string, bool) {
+	parts, ok := subroutes(route, prefix)
+	if !ok || len(parts) != 1 {
+		return "", false
+	}
+	return parts[0], true
+}
+
+// subroutes splits the part of route below prefix into its segments.
+func subroutes(route, prefix string) ([]string, bool) {
+	rest := strings.TrimPrefix(route, prefix+"/")
+	if rest == route || rest == "" {
+		return nil, false
+	}
+	parts := strings.Split(rest, "/")
+	for _, part := range parts {
+		if part == "" {
+			return nil, false
+		}
+	}
+	return parts, true
+}
+
+func (s *Server) serveTransactionRoute(w http.ResponseWriter, req *http.Request, parts []string) {
+	switch {
+	case len(parts) == 1:
+		s.handleTransaction(w, req, parts[0])
+	case len(parts) == 2 && parts[1] == "attachments":
+		s.handleAttachments(w, req, parts[0])
+	case len(parts) == 4 && parts[1] == "attachments" && parts[3] == "content":
+		s.handleAttachmentContent(w, req, parts[0], parts[2])
+	default:
+		writeErrors(w, http.StatusNotFound, "Not Found.")
+	}
+}
+
+func (s *Server) authorized(req *http.Request) bool {
+	accessToken := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiry, ok := s.accessTokens[accessToken]
+	return ok && time.Now().Before(expiry)
+}
+
+func (s *Server) handleAuth(w http.ResponseWriter, req *http.Request) {
+	if !requireMethod(w, req, "POST") {
+		return
+	}
+	username, password, ok := req.BasicAuth()
+	if !ok {
+		writeErrors(w, http.StatusBadRequest, `expecting "username" and "password" to be set`)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if saved, known := s.passwords[username]; !known || saved != password {
+		writeErrors(w, http.StatusForbidden, "invalid credentials")
+		return
+	}
+	writeResults(w, []*seedco.Token{s.issueTokenLocked()})
+}
+
+func (s *Server) handleRefresh(w http.ResponseWriter, req *http.Request) {
+	if !requireMethod(w, req, "POST") {
+		return
+	}
+	recv := make(map[string]string)
+	if err := json.NewDecoder(req.Body).Decode(&recv); err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	refreshToken := recv["refresh_token"]
+	if !s.refreshTokens[refreshToken] {
+		writeErrors(w, http.StatusUnauthorized, "invalid credentials")
+		return
+	}
+	// Refresh tokens are single use.
+	delete(s.refreshTokens, refreshToken)
+	writeResults(w, []*seedco.Token{s.issueTokenLocked()})
+}
+
+func (s *Server) handleBalances(w http.ResponseWriter, req *http.Request) {
+	if !requireMethod(w, req, "GET") {
+		return
+	}
+	s.mu.Lock()
+	balances := append([]*seedco.Balance{}, s.balances...)
+	s.mu.Unlock()
+	writeResults(w, balances)
+}
+
+func (s *Server) handleCheckingAccounts(w http.ResponseWriter, req *http.Request) {
+	if !requireMethod(w, req, "GET") {
+		return
+	}
+	s.mu.Lock()
+	accounts := append([]*seedco.CheckingAccount{}, s.accounts...)
+	s.mu.Unlock()
+	writeResults(w, accounts)
+}
+
+func (s *Server) handleCheckingAccount(w http.ResponseWriter, req *http.Request, id string) {
+	if !requireMethod(w, req, "GET") {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, acct := range s.accounts {
+		if acct.ID == id {
+			writeResults(w, []*seedco.CheckingAccount{acct})
+			return
+		}
+	}
+	writeErrors(w, http.StatusNotFound, "checking account not found")
+}
+
+func (s *Server) handleAPIVersion(w http.ResponseWriter, req *http.Request) {
+	if !requireMethod(w, req, "POST") {
+		return
+	}
+	s.mu.Lock()
+	v := s.apiVersion
+	s.mu.Unlock()
+	writeResults(w, []*seedco.APIVersion{v})
+}
+
+func (s *Server) handleTransactions(w http.ResponseWriter, req *http.Request) {
+	if !requireMethod(w, req, "GET") {
+		return
+	}
+	query := req.URL.Query()
+	f := &transactionFilter{
+		query:  strings.ToLower(query.Get("query")),
+		status: seedco.Status(query.Get("status")),
+	}
+	var err error
+	if f.start, err = parseDate(query.Get("start_date")); err != nil {
+		writeErrors(w, http.StatusBadRequest, "start_date: "+err.Error())
+		return
+	}
+	if f.end, err = parseDate(query.Get("end_date")); err != nil {
+		writeErrors(w, http.StatusBadRequest, "end_date: "+err.Error())
+		return
+	}
+	offset, err := parseNonNegative(query.Get("offset"))
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, "offset: "+err.Error())
+		return
+	}
+	limit, err := parseNonNegative(query.Get("limit"))
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, "limit: "+err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	matches := make([]*seedco.Transaction, 0, len(s.transactions))
+	for _, t := range s.transactions {
+		if f.matches(t) {
+			matches = append(matches, s.withAttachmentsLocked(t))
+		}
+	}
+	s.mu.Unlock()
+
+	if offset > len(matches) {
+		offset = len(matches)
+	}
+	matches = matches[offset:]
+	if limit > 0 && limit < len(matches) {
+		matches = matches[:limit]
+	}
+	writeResults(w, matches)
+}
+
+func (s *Server) handleTransaction(w http.ResponseWriter, req *http.Request, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.transactionLocked(id)
+	if t == nil {
+		writeErrors(w, http.StatusNotFound, "transaction not found")
+		return
+	}
+
+	switch req.Method {
+	case "GET":
+	case "PATCH":
+		if ifMatch := req.Header.Get("If-Match"); ifMatch != "" && ifMatch != etagOf(t) {
+			writeErrors(w, http.StatusPreconditionFailed, "transaction was modified")
+			return
+		}
+		update := new(seedco.TransactionUpdate)
+		if err := json.NewDecoder(req.Body).Decode(update); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		// Replace rather than mutate the transaction
+		// since callers may still hold on to it.
+		updated := new(seedco.Transaction)
+		*updated = *t
+		if update.Memo != nil {
+			updated.Memo = *update.Memo
+		}
+		if update.Category != nil {
+			updated.Category = *update.Category
+		}
+		s.replaceTransactionLocked(updated)
+		t = updated
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed, "Method Not Allowed.")
+		return
+	}
+	w.Header().Set("ETag", etagOf(t))
+	writeResults(w, []*seedco.Transaction{s.withAttachmentsLocked(t)})
+}
+
+func (s *Server) replaceTransactionLocked(t *seedco.Transaction) {
+	for i, cur := range s.transactions {
+		if cur.ID == t.ID {
+			s.transactions[i] = t
+			return
+		}
+	}
+}
+
+// etagOf derives an ETag from the content of t.
+func etagOf(t *seedco.Transaction) string {
+	blob, _ := json.Marshal(t)
+	sum := sha256.Sum256(blob)
+	return `"` + hex.EncodeToString(sum[:8]) + `"`
+}
+
+func (s *Server) transactionLocked(id string) *seedco.Transaction {
+	for _, t := range s.transactions {
+		if t.ID == id {
+			return t
+		}
+	}
+	return nil
+}
+
+func (s *Server) servePaymentRoute(w http.ResponseWriter, req *http.Request, parts []string) {
+	switch {
+	case len(parts) == 1:
+		if requireMethod(w, req, "GET") {
+			s.handlePayment(w, parts[0])
+		}
+	case len(parts) == 2 && parts[1] == "cancel":
+		if requireMethod(w, req, "POST") {
+			s.handleCancelPayment(w, parts[0])
+		}
+	default:
+		writeErrors(w, http.StatusNotFound, "Not Found.")
+	}
+}
+
+func (s *Server) handlePayments(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case "GET":
+		query := req.URL.Query()
+		status := seedco.PaymentStatus(query.Get("status"))
+		accountID := query.Get("checking_account_id")
+		s.mu.Lock()
+		matches := make([]*seedco.Payment, 0, len(s.payments))
+		for _, p := range s.payments {
+			if status != "" && p.Status != status {
+				continue
+			}
+			if accountID != "" && p.FromCheckingAccountID != accountID {
+				continue
+			}
+			matches = append(matches, p)
+		}
+		s.mu.Unlock()
+		writeResults(w, matches)
+	case "POST":
+		s.handleCreatePayment(w, req)
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed, "Method Not Allowed.")
+	}
+}
+
+func (s *Server) handleCreatePayment(w http.ResponseWriter, req *http.Request) {
+	pr := new(seedco.PaymentRequest)
+	if err := json.NewDecoder(req.Body).Decode(pr); err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := pr.Validate(); err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Like the API, replay the original payment
+	// for a repeated Idempotency-Key.
+	key := req.Header.Get("Idempotency-Key")
+	if prev, ok := s.paymentKeys[key]; ok && key != "" {
+		writeResults(w, []*seedco.Payment{prev})
+		return
+	}
+	counterparty := pr.Counterparty
+	if pr.PayeeID != "" {
+		i := s.payeeIndexLocked(pr.PayeeID)
+		if i < 0 {
+			writeErrors(w, http.StatusBadRequest, "payee not found")
+			return
+		}
+		counterparty = s.payees[i].Counterparty()
+	}
+	now := time.Now().UTC()
+	p := &seedco.Payment{
+		ID:                    randomID(),
+		Type:                  pr.Type,
+		Status:                seedco.PaymentPending,
+		FromCheckingAccountID: pr.FromCheckingAccountID,
+		ToCheckingAccountID:   pr.ToCheckingAccountID,
+		Counterparty:          counterparty,
+		PayeeID:               pr.PayeeID,
+		AmountCents:           pr.AmountCents,
+		Memo:                  pr.Memo,
+		ScheduledFor:          pr.ScheduledFor,
+		CreatedAt:             &now,
+	}
+	if p.ScheduledFor != nil && p.ScheduledFor.After(now) {
+		p.Status = seedco.PaymentScheduled
+	}
+	s.payments = append(s.payments, p)
+	if key != "" {
+		s.paymentKeys[key] = p
+	}
+	writeResults(w, []*seedco.Payment{p})
+}
+
+func (s *Server) handlePayment(w http.ResponseWriter, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.paymentLocked(id)
+	if p == nil {
+		writeErrors(w, http.StatusNotFound, "payment not found")
+		return
+	}
+	writeResults(w, []*seedco.Payment{p})
+}
+
+func (s *Server) handleCancelPayment(w http.ResponseWriter, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.paymentLocked(id)
+	if p == nil {
+		writeErrors(w, http.StatusNotFound, "payment not found")
+		return
+	}
+	if p.Status != seedco.PaymentScheduled {
+		writeErrors(w, http.StatusConflict, "only scheduled payments can be canceled")
+		return
+	}
+	canceled := new(seedco.Payment)
+	*canceled = *p
+	canceled.Status = seedco.PaymentCanceled
+	s.replacePaymentLocked(canceled)
+	writeResults(w, []*seedco.Payment{canceled})
+}
+
+func (s *Server) paymentLocked(id string) *seedco.Payment {
+	for _, p := range s.payments {
+		if p.ID == id {
+			return p
+		}
+	}
+	return nil
+}
+
+func (s *Server) replacePaymentLocked(p *seedco.Payment) {
+	for i, cur := range s.payments {
+		if cur.ID == p.ID {
+			s.payments[i] = p
+			return
+		}
+	}
+}
+
+func (s *Server) handlePayees(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case "GET":
+		s.mu.Lock()
+		payees := append([]*seedco.Payee{}, s.payees...)
+		s.mu.Unlock()
+		writeResults(w, payees)
+	case "POST":
+		p := new(seedco.Payee)
+		if err := json.NewDecoder(req.Body).Decode(p); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := p.Validate(); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		now := time.Now().UTC()
+		p.ID = randomID()
+		p.CreatedAt = &now
+		s.mu.Lock()
+		s.payees = append(s.payees, p)
+		s.mu.Unlock()
+		writeResults(w, []*seedco.Payee{p})
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed, "Method Not Allowed.")
+	}
+}
+
+func (s *Server) handlePayee(w http.ResponseWriter, req *http.Request, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.payeeIndexLocked(id)
+	if i < 0 {
+		writeErrors(w, http.StatusNotFound, "payee not found")
+		return
+	}
+
+	switch req.Method {
+	case "GET":
+		writeResults(w, []*seedco.Payee{s.payees[i]})
+	case "PATCH":
+		update := new(seedco.PayeeUpdate)
+		if err := json.NewDecoder(req.Body).Decode(update); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := update.Validate(); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		// Replace rather than mutate the payee
+		// since callers may still hold on to it.
+		updated := new(seedco.Payee)
+		*updated = *s.payees[i]
+		if update.Name != nil {
+			updated.Name = *update.Name
+		}
+		if update.Email != nil {
+			updated.Email = *update.Email
+		}
+		if update.RoutingNumber != nil {
+			updated.RoutingNumber = *update.RoutingNumber
+		}
+		if update.AccountNumber != nil {
+			updated.AccountNumber = *update.AccountNumber
+		}
+		if update.AccountType != nil {
+			updated.AccountType = *update.AccountType
+		}
+		s.payees[i] = updated
+		writeResults(w, []*seedco.Payee{updated})
+	case "DELETE":
+		s.payees = append(s.payees[:i], s.payees[i+1:]...)
+		writeResults(w, []*seedco.Payee{})
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed, "Method Not Allowed.")
+	}
+}
+
+func (s *Server) payeeIndexLocked(id string) int {
+	for i, p := range s.payees {
+		if p.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+type storedAttachment struct {
+	meta    *seedco.Attachment
+	content []byte
+}
+
+// AddAttachment attaches content to the transaction with the given ID
+// as if it had been uploaded, and returns the attachment's metadata.
+func (s *Server) AddAttachment(transactionID, filename, contentType string, content []byte) *seedco.Attachment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addAttachmentLocked(transactionID, filename, contentType, content)
+}
+
+func (s *Server) addAttachmentLocked(transactionID, filename, contentType string, content []byte) *seedco.Attachment {
+	now := time.Now().UTC()
+	id := randomID()
+	meta := &seedco.Attachment{
+		ID:            id,
+		TransactionID: transactionID,
+		Filename:      filename,
+		ContentType:   contentType,
+		Size:          int64(len(content)),
+		URL:           basePath + TransactionsRoute + "/" + transactionID + "/attachments/" + id + "/content",
+		CreatedAt:     &now,
+	}
+	s.attachments[transactionID] = append(s.attachments[transactionID], &storedAttachment{meta: meta, content: content})
+	return meta
+}
+
+// AttachmentContent returns the content of an attachment, if it exists.
+func (s *Server) AttachmentContent(transactionID, attachmentID string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sa := s.attachmentLocked(transactionID, attachmentID); sa != nil {
+		return sa.content, true
+	}
+	return nil, false
+}
+
+func (s *Server) attachmentLocked(transactionID, attachmentID string) *storedAttachment {
+	for _, sa := range s.attachments[transactionID] {
+		if sa.meta.ID == attachmentID {
+			return sa
+		}
+	}
+	return nil
+}
+
+// withAttachmentsLocked returns a copy of t listing its attachments.
+func (s *Server) withAttachmentsLocked(t *seedco.Transaction) *seedco.Transaction {
+	stored := s.attachments[t.ID]
+	if len(stored) == 0 {
+		return t
+	}
+	tc := new(seedco.Transaction)
+	*tc = *t
+	tc.Attachments = append([]*seedco.Attachment(nil), t.Attachments...)
+	for _, sa := range stored {
+		tc.Attachments = append(tc.Attachments, sa.meta)
+	}
+	return tc
+}
+
+// maxUploadSize bounds the size of attachments that the fake accepts.
+const maxUploadSize = 32 << 20
+
+func (s *Server) handleAttachments(w http.ResponseWriter, req *http.Request, transactionID string) {
+	s.mu.Lock()
+	known := s.transactionLocked(transactionID) != nil
+	s.mu.Unlock()
+	if !known {
+		writeErrors(w, http.StatusNotFound, "transaction not found")
+		return
+	}
+
+	switch req.Method {
+	case "GET":
+		s.mu.Lock()
+		metas := make([]*seedco.Attachment, 0, len(s.attachments[transactionID]))
+		for _, sa := range s.attachments[transactionID] {
+			metas = append(metas, sa.meta)
+		}
+		s.mu.Unlock()
+		writeResults(w, metas)
+
+	case "POST":
+		if err := req.ParseMultipartForm(maxUploadSize); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		f, fh, err := req.FormFile("file")
+		if err != nil {
+			writeErrors(w, http.StatusBadRequest, "file: "+err.Error())
+			return
+		}
+		defer f.Close()
+		content, err := ioutil.ReadAll(f)
+		if err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.mu.Lock()
+		meta := s.addAttachmentLocked(transactionID, fh.Filename, fh.Header.Get("Content-Type"), content)
+		s.mu.Unlock()
+		writeJSON(w, http.StatusCreated, &envelope{Errors: []*seedco.Error{}, Results: []*seedco.Attachment{meta}})
+
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed, "Method Not Allowed.")
+	}
+}
+
+func (s *Server) handleAttachmentContent(w http.ResponseWriter, req *http.Request, transactionID, attachmentID string) {
+	if !requireMethod(w, req, "GET") {
+		return
+	}
+	s.mu.Lock()
+	sa := s.attachmentLocked(transactionID, attachmentID)
+	s.mu.Unlock()
+	if sa == nil {
+		writeErrors(w, http.StatusNotFound, "attachment not found")
+		return
+	}
+	if sa.meta.ContentType != "" {
+		w.Header().Set("Content-Type", sa.meta.ContentType)
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(sa.content)
+}
+
+type transactionFilter struct {
+	query      string
+	status     seedco.Status
+	start, end time.Time
+}
+
+func (f *transactionFilter) matches(t *seedco.Transaction) bool {
+	if f.status != "" && t.Status != f.status {
+		return false
+	}
+	date := dateOf(t)
+	if !f.start.IsZero() && date.Before(f.start) {
+		return false
+	}
+	if !f.end.IsZero() && date.After(f.end) {
+		return false
+	}
+	if f.query == "" {
+		return true
+	}
+	for _, field := range []string{t.Description, t.Memo, string(t.Category)} {
+		if strings.Contains(strings.ToLower(field), f.query) {
+			return true
+		}
+	}
+	return false
+}
+
+func dateOf(t *seedco.Transaction) time.Time {
+	if t.Date == nil {
+		return time.Time{}
+	}
+	return *t.Date
+}
+
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+func parseNonNegative(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, strconv.ErrRange
+	}
+	return n, nil
+}
+
+func requireMethod(w http.ResponseWriter, req *http.Request, method string) bool {
+	if req.Method == method {
+		return true
+	}
+	writeErrors(w, http.StatusMethodNotAllowed, "Method Not Allowed.")
+	return false
+}
+
+type envelope struct {
+	Errors  []*seedco.Error `json:"errors"`
+	Results interface{}     `json:"results"`
+}
+
+func writeResults(w http.ResponseWriter, results interface{}) {
+	writeJSON(w, http.StatusOK, &envelope{Errors: []*seedco.Error{}, Results: results})
+}
+
+func writeErrors(w http.ResponseWriter, code int, msgs ...string) {
+	errs := make([]*seedco.Error, 0, len(msgs))
+	for _, msg := range msgs {
+		errs = append(errs, &seedco.Error{Message: msg})
+	}
+	writeJSON(w, code, &envelope{Errors: errs, Results: []interface{}{}})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", randomID())
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// NewWebhookRequest returns a webhook delivery of an event of type typ
+// about data, addressed to target and signed with secret, to exercise
+// a seedco.WebhookHandler with.
+func NewWebhookRequest(target string, secret []byte, typ seedco.EventType, data interface{}) (*http.Request, error) {
+	blob, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	ev := &seedco.Event{
+		ID:        randomID(),
+		Type:      typ,
+		CreatedAt: time.Now().UTC(),
+		Data:      blob,
+	}
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("POST", target, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(seedco.WebhookSignatureHeader, seedco.SignWebhook(secret, time.Now(), body))
+	return req, nil
+}
+
+func randomID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,135 @@
+package seedcotest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/orijtech/seedco/v1"
+	"github.com/orijtech/seedco/v1/seedcotest"
+)
+
+func day(d int) *time.Time {
+	t := time.Date(2017, time.October, d, 12, 0, 0, 0, time.UTC)
+	return &t
+}
+
+func newServers() map[string]*seedcotest.Server {
+	return map[string]*seedcotest.Server{
+		"http":       seedcotest.NewServer(),
+		"in-process": seedcotest.NewUnstartedServer(),
+	}
+}
+
+func TestAuthAndRefresh(t *testing.T) {
+	for name, srv := range newServers() {
+		defer srv.Close()
+		srv.AddUser("foo-username", "foo-password")
+		srv.SetBalances(&seedco.Balance{CheckingAccountID: "acct-1", Accessible: 100})
+
+		client, err := srv.Client()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.AuthToken("foo-username", "bar"); err == nil {
+			t.Errorf("%s: invalid credentials: want non-nil error", name)
+		}
+		tok, err := client.AuthToken("foo-username", "foo-password")
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		client.SetToken(tok)
+
+		// An expired access token is transparently refreshed.
+		srv.ExpireToken(tok.AccessToken)
+		balances, err := client.ListBalances()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if g, w := len(balances), 1; g != w {
+			t.Errorf("%s: balances: got=%d want=%d", name, g, w)
+		}
+		if g, w := srv.Requests(seedcotest.RefreshRoute), 1; g != w {
+			t.Errorf("%s: refreshes: got=%d want=%d", name, g, w)
+		}
+		if _, err := client.RefreshToken(tok.RefreshToken); err == nil {
+			t.Errorf("%s: reused refresh token: want non-nil error", name)
+		}
+	}
+}
+
+func TestTransactionQuerySemantics(t *testing.T) {
+	srv := seedcotest.NewUnstartedServer()
+	srv.AddTransactions(
+		&seedco.Transaction{ID: "t1", Description: "Chipotle", Status: seedco.Settled, Date: day(1)},
+		&seedco.Transaction{ID: "t2", Description: "Uber", Memo: "to chipotle", Status: seedco.Pending, Date: day(2)},
+		&seedco.Transaction{ID: "t3", Description: "Mcdonalds", Status: seedco.Settled, Date: day(3)},
+		&seedco.Transaction{ID: "t4", Description: "Chipotle", Status: seedco.Settled, Date: day(4)},
+	)
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := [...]struct {
+		params  *seedco.SearchParams
+		wantIDs []string
+	}{
+		0: {&seedco.SearchParams{}, []string{"t4", "t3", "t2", "t1"}},
+		1: {&seedco.SearchParams{Query: "CHIPOTLE"}, []string{"t4", "t2", "t1"}},
+		2: {&seedco.SearchParams{Query: "chipotle", Status: seedco.Settled}, []string{"t4", "t1"}},
+		3: {&seedco.SearchParams{StartDate: *day(2), EndDate: *day(3)}, []string{"t3", "t2"}},
+		4: {&seedco.SearchParams{Limit: 1, Offset: 1}, []string{"t3", "t2", "t1"}},
+		5: {&seedco.SearchParams{Limit: 1, Offset: 1, MaxPageNumber: 2}, []string{"t3", "t2"}},
+	}
+
+	ctx := context.Background()
+	for i, tt := range tests {
+		var gotIDs []string
+		it := client.Transactions(tt.params)
+		for {
+			txn, err := it.Next(ctx)
+			if err == seedco.Done {
+				break
+			}
+			if err != nil {
+				t.Fatalf("#%d: unexpected error: %v", i, err)
+			}
+			gotIDs = append(gotIDs, txn.ID)
+		}
+		if g, w := len(gotIDs), len(tt.wantIDs); g != w {
+			t.Errorf("#%d: got=%q want=%q", i, gotIDs, tt.wantIDs)
+			continue
+		}
+		for j := range gotIDs {
+			if gotIDs[j] != tt.wantIDs[j] {
+				t.Errorf("#%d: got=%q want=%q", i, gotIDs, tt.wantIDs)
+				break
+			}
+		}
+	}
+}
+
+func TestFailNext(t *testing.T) {
+	srv := seedcotest.NewServer()
+	defer srv.Close()
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv.FailNext(seedcotest.BalanceRoute, http.StatusTooManyRequests, "slow down")
+	_, err = client.ListBalances()
+	if !errors.Is(err, seedco.ErrRateLimited) {
+		t.Errorf("got=(%v) want=(%v)", err, seedco.ErrRateLimited)
+	}
+	var ae *seedco.APIError
+	if !errors.As(err, &ae) || ae.Error() == "" || len(ae.Errors) != 1 || ae.Errors[0].Message != "slow down" {
+		t.Errorf("got=(%#v) want an *APIError with message %q", err, "slow down")
+	}
+	if _, err := client.ListBalances(); err != nil {
+		t.Errorf("after injected failure: unexpected error: %v", err)
+	}
+}
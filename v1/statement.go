@@ -0,0 +1,247 @@
+package seedco
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// Statement lists the settled transactions of a checking account
+// over a period, with the balance after each of them.
+type Statement struct {
+	CheckingAccountID string    `json:"checking_account_id"`
+	Start             time.Time `json:"start"`
+	End               time.Time `json:"end"`
+
+	OpeningBalance Cents `json:"opening_balance"`
+	ClosingBalance Cents `json:"closing_balance"`
+
+	// TotalCredits and TotalDebits sum the money that came in and
+	// went out over the period, as changes to the balance, so that
+	// TotalDebits is negative; see Transaction.BalanceChange.
+	TotalCredits Cents `json:"total_credits"`
+	TotalDebits  Cents `json:"total_debits"`
+
+	// Lines are in chronological order.
+	Lines []*StatementLine `json:"lines"`
+
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+type StatementLine struct {
+	Transaction *Transaction `json:"transaction"`
+
+	// Amount is how much the transaction changed the
+	// balance, negative for debits, unlike its AmountCents.
+	Amount Cents `json:"amount"`
+
+	// Balance is the running balance after the transaction.
+	Balance Cents `json:"balance"`
+}
+
+var (
+	errStatementPeriod    = errors.New("statement start must not be after its end")
+	errBlankStatementAcct = errors.New("statement checking account ID must be non-blank")
+	errNoAccountBalance   = errors.New("no balance received for the checking account")
+)
+
+// Statement builds the statement of the settled transactions of the
+// checking account dated from start through the whole day of end, in
+// end's location, so that end may be given as a date. Since the API only
+// reports the current balance, the balances are worked out backwards
+// from it, which requires fetching every transaction since start.
+func (c *Client) Statement(ctx context.Context, checkingAccountID string, start, end time.Time) (*Statement, error) {
+	if checkingAccountID == "" {
+		return nil, errBlankStatementAcct
+	}
+	if start.After(end) {
+		return nil, errStatementPeriod
+	}
+
+	balances, err := c.ListBalancesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var current *Balance
+	for _, b := range balances {
+		if b != nil && b.CheckingAccountID == checkingAccountID {
+			current = b
+			break
+		}
+	}
+	if current == nil {
+		return nil, fmt.Errorf("%w %q", errNoAccountBalance, checkingAccountID)
+	}
+
+	afterEnd := time.Date(end.Year(), end.Month(), end.Day()+1, 0, 0, 0, 0, end.Location())
+	var inPeriod []*Transaction
+	var sinceEnd Cents
+	it := c.Transactions(&SearchParams{Status: Settled, StartDate: start})
+	for {
+		t, err := it.Next(ctx)
+		if err == Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if t.CheckingAccountID != checkingAccountID || t.Status != Settled || t.Date == nil || t.Date.Before(start) {
+			continue
+		}
+		if !t.Date.Before(afterEnd) {
+			change, err := t.BalanceChange()
+			if err != nil {
+				return nil, err
+			}
+			if sinceEnd, err = sinceEnd.Add(change); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		inPeriod = append(inPeriod, t)
+	}
+	sort.SliceStable(inPeriod, func(i, j int) bool {
+		return inPeriod[i].Date.Before(*inPeriod[j].Date)
+	})
+
+	st := &Statement{
+		CheckingAccountID: checkingAccountID,
+		Start:             start,
+		End:               end,
+		Lines:             make([]*StatementLine, 0, len(inPeriod)),
+		GeneratedAt:       time.Now().UTC(),
+	}
+	if st.ClosingBalance, err = current.Settled.Sub(sinceEnd); err != nil {
+		return nil, err
+	}
+	var net Cents
+	for _, t := range inPeriod {
+		change, err := t.BalanceChange()
+		if err != nil {
+			return nil, err
+		}
+		if net, err = net.Add(change); err != nil {
+			return nil, err
+		}
+		if change < 0 {
+			st.TotalDebits, err = st.TotalDebits.Add(change)
+		} else {
+			st.TotalCredits, err = st.TotalCredits.Add(change)
+		}
+		if err != nil {
+			return nil, err
+		}
+		st.Lines = append(st.Lines, &StatementLine{Transaction: t, Amount: change})
+	}
+	if st.OpeningBalance, err = st.ClosingBalance.Sub(net); err != nil {
+		return nil, err
+	}
+	running := st.OpeningBalance
+	for _, line := range st.Lines {
+		if running, err = running.Add(line.Amount); err != nil {
+			return nil, err
+		}
+		line.Balance = running
+	}
+	return st, nil
+}
+
+const statementDateLayout = "2006-01-02"
+
+func (st *Statement) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(st)
+}
+
+// WriteCSV writes a row per line, between rows
+// for the opening and the closing balance.
+func (st *Statement) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	rows := [][]string{
+		{"date", "id", "description", "memo", "amount", "balance"},
+		{st.Start.Format(statementDateLayout), "", "Opening balance", "", "", st.OpeningBalance.Decimal()},
+	}
+	for _, line := range st.Lines {
+		t := line.Transaction
+		rows = append(rows, []string{t.Date.Format(statementDateLayout), t.ID, t.Description, t.Memo, line.Amount.Decimal(), line.Balance.Decimal()})
+	}
+	rows = append(rows, []string{st.End.Format(statementDateLayout), "", "Closing balance", "", "", st.ClosingBalance.Decimal()})
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// WriteText writes the statement as a plain text report.
+func (st *Statement) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "Statement for checking account %s\t\n", st.CheckingAccountID)
+	fmt.Fprintf(tw, "Period %s to %s\t\n\n", st.Start.Format(statementDateLayout), st.End.Format(statementDateLayout))
+	fmt.Fprintf(tw, "Opening balance\t%s\t\n", st.OpeningBalance)
+	fmt.Fprintf(tw, "Credits\t%s\t\n", st.TotalCredits)
+	fmt.Fprintf(tw, "Debits\t%s\t\n", st.TotalDebits)
+	fmt.Fprintf(tw, "Closing balance\t%s\t\n", st.ClosingBalance)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "DATE\tDESCRIPTION\tAMOUNT\tBALANCE\t")
+	for _, line := range st.Lines {
+		t := line.Transaction
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t\n", t.Date.Format(statementDateLayout), t.Description, line.Amount, line.Balance)
+	}
+	return tw.Flush()
+}
+
+var statementHTML = template.Must(template.New("statement").Funcs(template.FuncMap{
+	"date": func(t interface{}) string {
+		switch t := t.(type) {
+		case time.Time:
+			return t.Format(statementDateLayout)
+		case *time.Time:
+			return t.Format(statementDateLayout)
+		default:
+			return ""
+		}
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Statement for checking account {{.CheckingAccountID}}</title>
+</head>
+<body>
+<h1>Statement for checking account {{.CheckingAccountID}}</h1>
+<p>Period {{date .Start}} to {{date .End}}</p>
+<table class="summary">
+<tr><th>Opening balance</th><td>{{.OpeningBalance}}</td></tr>
+<tr><th>Credits</th><td>{{.TotalCredits}}</td></tr>
+<tr><th>Debits</th><td>{{.TotalDebits}}</td></tr>
+<tr><th>Closing balance</th><td>{{.ClosingBalance}}</td></tr>
+</table>
+<table class="lines">
+<thead><tr><th>Date</th><th>Description</th><th>Memo</th><th>Amount</th><th>Balance</th></tr></thead>
+<tbody>
+{{- range .Lines}}
+<tr><td>{{date .Transaction.Date}}</td><td>{{.Transaction.Description}}</td><td>{{.Transaction.Memo}}</td><td>{{.Amount}}</td><td>{{.Balance}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+</body>
+</html>
+`))
+
+// WriteHTML writes the statement as a standalone HTML page.
+func (st *Statement) WriteHTML(w io.Writer) error {
+	return statementHTML.Execute(w, st)
+}
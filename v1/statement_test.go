@@ -0,0 +1,223 @@
+package seedco_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orijtech/seedco/v1"
+	"github.com/orijtech/seedco/v1/seedcotest"
+)
+
+func statementServer(t *testing.T) *seedco.Client {
+	day := func(d int) *time.Time {
+		date := time.Date(2018, time.March, d, 12, 0, 0, 0, time.UTC)
+		return &date
+	}
+	srv := seedcotest.NewUnstartedServer()
+	srv.SetBalances(
+		&seedco.Balance{CheckingAccountID: "a1", Settled: 500000},
+		&seedco.Balance{CheckingAccountID: "a2", Settled: 100},
+	)
+	srv.AddTransactions(
+		// Before the period.
+		&seedco.Transaction{ID: "t0", CheckingAccountID: "a1", Status: seedco.Settled, AmountCents: -999, Date: day(1)},
+		&seedco.Transaction{ID: "t1", CheckingAccountID: "a1", Status: seedco.Settled, AmountCents: -100000, Description: "Stripe payout", Date: day(5)},
+		&seedco.Transaction{ID: "t2", CheckingAccountID: "a1", Status: seedco.Settled, AmountCents: 25000, Description: "Rent <March>", Date: day(10)},
+		&seedco.Transaction{ID: "t3", CheckingAccountID: "a1", Status: seedco.Pending, AmountCents: 7000, Description: "Pending", Date: day(12)},
+		&seedco.Transaction{ID: "t4", CheckingAccountID: "a2", Status: seedco.Settled, AmountCents: 300, Description: "Other account", Date: day(12)},
+		&seedco.Transaction{ID: "t5", CheckingAccountID: "a1", Status: seedco.Settled, AmountCents: 5000, Description: "Uber", Date: day(15)},
+		// After the period.
+		&seedco.Transaction{ID: "t6", CheckingAccountID: "a1", Status: seedco.Settled, AmountCents: -20000, Description: "Refund", Date: day(25)},
+	)
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestStatement(t *testing.T) {
+	client := statementServer(t)
+	start := time.Date(2018, time.March, 5, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2018, time.March, 20, 0, 0, 0, 0, time.UTC)
+
+	st, err := client.Statement(context.Background(), "a1", start, end)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Working backwards from 5000.00 settled: the closing balance
+	// excludes the later refund, the opening one the period's net.
+	if g, w := st.ClosingBalance, seedco.Cents(480000); g != w {
+		t.Errorf("closing balance: got=%v want=%v", g, w)
+	}
+	if g, w := st.OpeningBalance, seedco.Cents(410000); g != w {
+		t.Errorf("opening balance: got=%v want=%v", g, w)
+	}
+	if g, w := st.TotalCredits, seedco.Cents(100000); g != w {
+		t.Errorf("credits: got=%v want=%v", g, w)
+	}
+	if g, w := st.TotalDebits, seedco.Cents(-30000); g != w {
+		t.Errorf("debits: got=%v want=%v", g, w)
+	}
+
+	want := []struct {
+		id      string
+		balance seedco.Cents
+	}{
+		{"t1", 510000},
+		{"t2", 485000},
+		{"t5", 480000},
+	}
+	if g, w := len(st.Lines), len(want); g != w {
+		t.Fatalf("lines: got=%d want=%d", g, w)
+	}
+	for i, line := range st.Lines {
+		if line.Transaction.ID != want[i].id || line.Balance != want[i].balance {
+			t.Errorf("#%d: got=(%s, %v) want=(%s, %v)", i, line.Transaction.ID, line.Balance, want[i].id, want[i].balance)
+		}
+	}
+
+	if _, err := client.Statement(context.Background(), "a3", start, end); err == nil {
+		t.Errorf("unknown account: want non-nil error")
+	}
+	if _, err := client.Statement(context.Background(), "a1", end, start); err == nil {
+		t.Errorf("inverted period: want non-nil error")
+	}
+}
+
+// The fixture lists a purchase with a positive amount,
+// which lowers the balance over the statement's period.
+func TestStatementFixture(t *testing.T) {
+	blob, err := os.ReadFile("./testdata/transactions-0,2.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp struct {
+		Results []*seedco.Transaction `json:"results"`
+	}
+	if err := json.Unmarshal(blob, &resp); err != nil {
+		t.Fatal(err)
+	}
+	const acctID = "9d9d21c7-2926-498e-b087-128f8b0e8b42"
+	srv := seedcotest.NewUnstartedServer()
+	srv.SetBalances(&seedco.Balance{CheckingAccountID: acctID, Settled: 10000})
+	srv.AddTransactions(resp.Results...)
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2016, time.December, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2016, time.December, 31, 0, 0, 0, 0, time.UTC)
+	st, err := client.Statement(context.Background(), acctID, start, end)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g, w := st.OpeningBalance, seedco.Cents(10736); g != w {
+		t.Errorf("opening balance: got=%v want=%v", g, w)
+	}
+	if g, w := st.ClosingBalance, seedco.Cents(10000); g != w {
+		t.Errorf("closing balance: got=%v want=%v", g, w)
+	}
+	if st.TotalDebits != -736 || st.TotalCredits != 0 {
+		t.Errorf("totals: got=(%v, %v) want=(-$7.36, $0.00)", st.TotalDebits, st.TotalCredits)
+	}
+	if len(st.Lines) != 1 {
+		t.Fatalf("lines: got=%d want=1", len(st.Lines))
+	}
+	if line := st.Lines[0]; line.Transaction.Description != "Mcdonalds" || line.Amount != -736 || line.Balance != 10000 {
+		t.Errorf("line: got=(%s, %v, %v) want=(Mcdonalds, -$7.36, $100.00)", line.Transaction.Description, line.Amount, line.Balance)
+	}
+}
+
+func TestStatementEndDateInclusive(t *testing.T) {
+	at := func(d, h int) *time.Time {
+		date := time.Date(2018, time.March, d, h, 0, 0, 0, time.UTC)
+		return &date
+	}
+	srv := seedcotest.NewUnstartedServer()
+	srv.SetBalances(&seedco.Balance{CheckingAccountID: "a1", Settled: 10000})
+	srv.AddTransactions(
+		&seedco.Transaction{ID: "t1", CheckingAccountID: "a1", Status: seedco.Settled, AmountCents: 500, Date: at(20, 12)},
+		&seedco.Transaction{ID: "t2", CheckingAccountID: "a1", Status: seedco.Settled, AmountCents: -100, Date: at(21, 0)},
+	)
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The period ends with the whole of March 20th.
+	st, err := client.Statement(context.Background(), "a1", *at(1, 0), *at(20, 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(st.Lines) != 1 || st.Lines[0].Transaction.ID != "t1" {
+		t.Fatalf("lines: got=%+v want only t1", st.Lines)
+	}
+	// The refund after the period is taken out of the closing balance.
+	if st.ClosingBalance != 9900 || st.OpeningBalance != 10400 || st.Lines[0].Balance != 9900 {
+		t.Errorf("balances: got opening=%v closing=%v line=%v want $104.00, $99.00, $99.00",
+			st.OpeningBalance, st.ClosingBalance, st.Lines[0].Balance)
+	}
+}
+
+func TestStatementRendering(t *testing.T) {
+	client := statementServer(t)
+	start := time.Date(2018, time.March, 5, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2018, time.March, 20, 0, 0, 0, 0, time.UTC)
+	st, err := client.Statement(context.Background(), "a1", start, end)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := st.WriteCSV(buf); err != nil {
+		t.Fatal(err)
+	}
+	wantCSV := "date,id,description,memo,amount,balance\n" +
+		"2018-03-05,,Opening balance,,,4100.00\n" +
+		"2018-03-05,t1,Stripe payout,,1000.00,5100.00\n" +
+		"2018-03-10,t2,Rent <March>,,-250.00,4850.00\n" +
+		"2018-03-15,t5,Uber,,-50.00,4800.00\n" +
+		"2018-03-20,,Closing balance,,,4800.00\n"
+	if g, w := buf.String(), wantCSV; g != w {
+		t.Errorf("csv:\ngot=%q\nwant=%q", g, w)
+	}
+
+	buf.Reset()
+	if err := st.WriteJSON(buf); err != nil {
+		t.Fatal(err)
+	}
+	decoded := new(seedco.Statement)
+	if err := json.Unmarshal(buf.Bytes(), decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.OpeningBalance != st.OpeningBalance || len(decoded.Lines) != len(st.Lines) {
+		t.Errorf("json: got=%+v want=%+v", decoded, st)
+	}
+
+	buf.Reset()
+	if err := st.WriteText(buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Opening balance  $4,100.00", "Closing balance  $4,800.00", "2018-03-10  Rent <March>"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("text: missing %q in\n%s", want, buf)
+		}
+	}
+
+	buf.Reset()
+	if err := st.WriteHTML(buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Period 2018-03-05 to 2018-03-20", "Rent &lt;March&gt;", "<td>-$250.00</td>"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("html: missing %q in\n%s", want, buf)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package seedco
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// SyncEventKind describes how a transaction changed since the last sync.
+type SyncEventKind int
+
+const (
+	TransactionAdded SyncEventKind = iota + 1
+	TransactionUpdated
+	TransactionRemoved
+)
+
+func (k SyncEventKind) String() string {
+	switch k {
+	case TransactionAdded:
+		return "added"
+	case TransactionUpdated:
+		return "updated"
+	case TransactionRemoved:
+		return "removed"
+	default:
+		return "unknown"
+	}
+}
+
+type SyncEvent struct {
+	Kind SyncEventKind
+
+	// Transaction is the latest version of the transaction, or
+	// for TransactionRemoved, the last version that was seen.
+	Transaction *Transaction
+
+	// Previous is the previously seen version for TransactionUpdated.
+	Previous *Transaction
+}
+
+// SyncCursor records where the previous sync left off.
+type SyncCursor struct {
+	// LastDate is the date of the newest transaction seen so far.
+	LastDate time.Time `json:"last_date,omitempty"`
+
+	// Recent are the transactions dated within the overlap
+	// window before LastDate, keyed by ID. They are fetched
+	// again on the next sync to catch late arrivals and edits.
+	Recent map[string]*Transaction `json:"recent,omitempty"`
+
+	// Pending are the transactions that had not settled yet,
+	// keyed by ID. They are re-checked until they settle.
+	Pending map[string]*Transaction `json:"pending,omitempty"`
+
+	SyncedAt time.Time `json:"synced_at,omitempty"`
+}
+
+// CursorStore persists SyncCursors between syncs.
+type CursorStore interface {
+	// LoadCursor returns the cursor saved under key,
+	// or nil and no error if there is none yet.
+	LoadCursor(ctx context.Context, key string) (*SyncCursor, error)
+	SaveCursor(ctx context.Context, key string, cur *SyncCursor) error
+}
+
+// Syncer incrementally syncs transactions. Each call to Sync only
+// fetches transactions dated since the previous sync, minus Overlap,
+// together with those that were still pending, and reports what
+// was added, updated or removed since then.
+type Syncer struct {
+	Client *Client
+	Store  CursorStore
+
+	// Key names the cursor in Store, allowing several
+	// independent syncs to share a store.
+	Key string
+
+	// Params optionally narrows the sync with a Query or Status.
+	// Its StartDate is the earliest date of the first sync;
+	// Offset and MaxPageNumber are ignored.
+	Params SearchParams
+
+	// Overlap is how far before the newest seen transaction
+	// the next sync starts looking. It defaults to 72 hours.
+	Overlap time.Duration
+}
+
+const defaultSyncOverlap = 72 * time.Hour
+
+var (
+	errNilSyncClient = errors.New("syncer: expecting a non-nil Client")
+	errNilSyncStore  = errors.New("syncer: expecting a non-nil Store")
+)
+
+// Sync fetches the changes since the last sync and passes each one to
+// fn. The cursor is only saved once every event was handled without
+// error, so that a failed sync is retried in full the next time.
+func (s *Syncer) Sync(ctx context.Context, fn func(*SyncEvent) error) (*SyncCursor, error) {
+	if s.Client == nil {
+		return nil, errNilSyncClient
+	}
+	if s.Store == nil {
+		return nil, errNilSyncStore
+	}
+	prev, err := s.Store.LoadCursor(ctx, s.Key)
+	if err != nil {
+		return nil, err
+	}
+	if prev == nil {
+		prev = new(SyncCursor)
+	}
+
+	overlap := s.Overlap
+	if overlap <= 0 {
+		overlap = defaultSyncOverlap
+	}
+
+	sp := s.Params
+	sp.Offset, sp.MaxPageNumber = 0, 0
+	if start, ok := prev.startDate(overlap); ok {
+		sp.StartDate = start
+	}
+
+	var fetched []*Transaction
+	it := s.Client.Transactions(&sp)
+	for {
+		t, err := it.Next(ctx)
+		if err == Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		fetched = append(fetched, t)
+	}
+
+	events, next := diffSync(prev, fetched, overlap)
+	for _, ev := range events {
+		if err := fn(ev); err != nil {
+			return nil, err
+		}
+	}
+	next.SyncedAt = time.Now().UTC()
+	if err := s.Store.SaveCursor(ctx, s.Key, next); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// startDate returns the earliest date that the next sync must cover.
+func (cur *SyncCursor) startDate(overlap time.Duration) (time.Time, bool) {
+	if cur.LastDate.IsZero() {
+		return time.Time{}, false
+	}
+	start := cur.LastDate.Add(-overlap)
+	for _, t := range cur.Pending {
+		if t.Date != nil && t.Date.Before(start) {
+			start = *t.Date
+		}
+	}
+	return start, true
+}
+
+// diffSync compares the freshly fetched transactions to those known to
+// prev and returns the resulting events along with the next cursor.
+func diffSync(prev *SyncCursor, fetched []*Transaction, overlap time.Duration) ([]*SyncEvent, *SyncCursor) {
+	var events []*SyncEvent
+	next := &SyncCursor{
+		LastDate: prev.LastDate,
+		Recent:   make(map[string]*Transaction),
+		Pending:  make(map[string]*Transaction),
+	}
+
+	// Only the re-check of old pending transactions reaches further
+	// back than this, and what it finds there was reported already.
+	var prevWindowStart time.Time
+	if !prev.LastDate.IsZero() {
+		prevWindowStart = prev.LastDate.Add(-overlap)
+	}
+
+	seen := make(map[string]bool)
+	for _, t := range fetched {
+		if t == nil || seen[t.ID] {
+			continue
+		}
+		seen[t.ID] = true
+
+		known := prev.Pending[t.ID]
+		if known == nil {
+			known = prev.Recent[t.ID]
+		}
+		switch {
+		case known == nil && t.Date != nil && t.Date.Before(prevWindowStart):
+		case known == nil:
+			events = append(events, &SyncEvent{Kind: TransactionAdded, Transaction: t})
+		case transactionChanged(known, t):
+			events = append(events, &SyncEvent{Kind: TransactionUpdated, Transaction: t, Previous: known})
+		}
+		if t.Date != nil && t.Date.After(next.LastDate) {
+			next.LastDate = *t.Date
+		}
+	}
+
+	// Pending transactions that are no longer returned were dropped.
+	removedIDs := make([]string, 0, len(prev.Pending))
+	for id := range prev.Pending {
+		if !seen[id] {
+			removedIDs = append(removedIDs, id)
+		}
+	}
+	sort.Strings(removedIDs)
+	for _, id := range removedIDs {
+		events = append(events, &SyncEvent{Kind: TransactionRemoved, Transaction: prev.Pending[id]})
+	}
+
+	windowStart := next.LastDate.Add(-overlap)
+	for _, t := range fetched {
+		if t == nil {
+			continue
+		}
+		if t.Status == Pending {
+			next.Pending[t.ID] = t
+		} else if t.Date != nil && !t.Date.Before(windowStart) {
+			next.Recent[t.ID] = t
+		}
+	}
+	return events, next
+}
+
+func transactionChanged(a, b *Transaction) bool {
+	if a.Status != b.Status || a.AmountCents != b.AmountCents ||
+		a.CheckingAccountID != b.CheckingAccountID || a.Category != b.Category ||
+		a.Description != b.Description || a.Memo != b.Memo ||
+		len(a.Attachments) != len(b.Attachments) {
+		return true
+	}
+	switch {
+	case a.Date == nil && b.Date == nil:
+		return false
+	case a.Date == nil || b.Date == nil:
+		return true
+	default:
+		return !a.Date.Equal(*b.Date)
+	}
+}
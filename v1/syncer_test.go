@@ -0,0 +1,211 @@
+package seedco_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/orijtech/seedco/v1"
+)
+
+func TestSyncer(t *testing.T) {
+	day := func(d int) *time.Time {
+		t := time.Date(2017, time.October, d, 12, 0, 0, 0, time.UTC)
+		return &t
+	}
+
+	tb := &transactionsBackend{transactions: []*seedco.Transaction{
+		{ID: "t1", AmountCents: 100, Status: seedco.Settled, Date: day(1)},
+		{ID: "t2", AmountCents: 200, Status: seedco.Pending, Date: day(2)},
+		{ID: "t3", AmountCents: 300, Status: seedco.Pending, Date: day(2)},
+	}}
+	client, err := seedco.NewClientWithToken(token1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetHTTPRoundTripper(tb)
+	client.SetRateLimiter(seedco.Unlimited)
+
+	store := seedco.NewMemoryCursorStore()
+	syncer := &seedco.Syncer{
+		Client:  client,
+		Store:   store,
+		Key:     "nightly",
+		Params:  seedco.SearchParams{Limit: 2},
+		Overlap: 24 * time.Hour,
+	}
+	ctx := context.Background()
+
+	sync := func() []string {
+		var got []string
+		if _, err := syncer.Sync(ctx, func(ev *seedco.SyncEvent) error {
+			got = append(got, ev.Kind.String()+":"+ev.Transaction.ID)
+			return nil
+		}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		sort.Strings(got)
+		return got
+	}
+
+	if g, w := sync(), []string{"added:t1", "added:t2", "added:t3"}; !reflect.DeepEqual(g, w) {
+		t.Errorf("first sync:\ngot: %q\nwant:%q", g, w)
+	}
+	if g := sync(); len(g) != 0 {
+		t.Errorf("unchanged sync: got %q want no events", g)
+	}
+
+	// t2 settles, t3 is dropped and t4 arrives much later.
+	tb.set([]*seedco.Transaction{
+		{ID: "t1", AmountCents: 100, Status: seedco.Settled, Date: day(1)},
+		{ID: "t2", AmountCents: 200, Status: seedco.Settled, Date: day(2)},
+		{ID: "t4", AmountCents: 400, Status: seedco.Settled, Date: day(20)},
+	})
+	if g, w := sync(), []string{"added:t4", "removed:t3", "updated:t2"}; !reflect.DeepEqual(g, w) {
+		t.Errorf("second sync:\ngot: %q\nwant:%q", g, w)
+	}
+
+	// Only transactions within the overlap window are fetched again.
+	tb.resetMinStartDate()
+	if g := sync(); len(g) != 0 {
+		t.Errorf("unchanged sync: got %q want no events", g)
+	}
+	if g, w := tb.minStartDate(), day(19); !g.Equal(*w) {
+		t.Errorf("startDate: got=%v want=%v", g, w)
+	}
+}
+
+func TestSyncerKeepsCursorOnHandlerError(t *testing.T) {
+	date := time.Date(2017, time.October, 1, 12, 0, 0, 0, time.UTC)
+	tb := &transactionsBackend{transactions: []*seedco.Transaction{
+		{ID: "t1", AmountCents: 100, Status: seedco.Settled, Date: &date},
+	}}
+	client, err := seedco.NewClientWithToken(token1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetHTTPRoundTripper(tb)
+
+	store := seedco.NewMemoryCursorStore()
+	syncer := &seedco.Syncer{Client: client, Store: store, Key: "k"}
+	errHandler := errors.New("handler failed")
+	if _, err := syncer.Sync(context.Background(), func(*seedco.SyncEvent) error { return errHandler }); err != errHandler {
+		t.Fatalf("got=(%v) want=(%v)", err, errHandler)
+	}
+	cur, err := store.LoadCursor(context.Background(), "k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cur != nil {
+		t.Errorf("cursor unexpectedly saved: %+v", cur)
+	}
+}
+
+func TestFileCursorStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "seedco-cursors")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store := &seedco.FileCursorStore{Dir: dir}
+	ctx := context.Background()
+
+	if cur, err := store.LoadCursor(ctx, "acct/1"); err != nil || cur != nil {
+		t.Fatalf("missing cursor: got=(%+v, %v) want=(nil, nil)", cur, err)
+	}
+	date := time.Date(2017, time.October, 1, 12, 0, 0, 0, time.UTC)
+	want := &seedco.SyncCursor{
+		LastDate: date,
+		Pending: map[string]*seedco.Transaction{
+			"t1": {ID: "t1", Status: seedco.Pending, Date: &date},
+		},
+	}
+	if err := store.SaveCursor(ctx, "acct/1", want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.LoadCursor(ctx, "acct/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got: %+v\nwant:%+v", got, want)
+	}
+}
+
+// transactionsBackend serves transactions with
+// start_date, offset and limit query semantics.
+type transactionsBackend struct {
+	mu           sync.Mutex
+	transactions []*seedco.Transaction
+	minStart     time.Time
+}
+
+var _ http.RoundTripper = (*transactionsBackend)(nil)
+
+func (tb *transactionsBackend) set(transactions []*seedco.Transaction) {
+	tb.mu.Lock()
+	tb.transactions = transactions
+	tb.mu.Unlock()
+}
+
+func (tb *transactionsBackend) resetMinStartDate() {
+	tb.mu.Lock()
+	tb.minStart = time.Time{}
+	tb.mu.Unlock()
+}
+
+func (tb *transactionsBackend) minStartDate() time.Time {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	return tb.minStart
+}
+
+func (tb *transactionsBackend) RoundTrip(req *http.Request) (*http.Response, error) {
+	if _, badRes, err := ensureBearerTokenAuthd(req); badRes != nil || err != nil {
+		return badRes, err
+	}
+	query := req.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	offset, _ := strconv.Atoi(query.Get("offset"))
+	var start time.Time
+	if s := query.Get("start_date"); s != "" {
+		var err error
+		if start, err = time.Parse(time.RFC3339, s); err != nil {
+			return makeResp(err.Error(), http.StatusBadRequest, nil)
+		}
+	}
+
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	if !start.IsZero() && (tb.minStart.IsZero() || start.Before(tb.minStart)) {
+		tb.minStart = start
+	}
+	var matches []*seedco.Transaction
+	for _, t := range tb.transactions {
+		if t.Date == nil || !t.Date.Before(start) {
+			matches = append(matches, t)
+		}
+	}
+	if offset > len(matches) {
+		offset = len(matches)
+	}
+	matches = matches[offset:]
+	if limit > 0 && limit < len(matches) {
+		matches = matches[:limit]
+	}
+	blob, err := json.Marshal(map[string]interface{}{"results": matches})
+	if err != nil {
+		return makeResp(err.Error(), http.StatusInternalServerError, nil)
+	}
+	return makeResp("200 OK", http.StatusOK, ioutil.NopCloser(strings.NewReader(string(blob))))
+}
@@ -0,0 +1,336 @@
+package seedco
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// TokenSource supplies the tokens that a Client authenticates with.
+// Its method set mirrors that of golang.org/x/oauth2.TokenSource.
+type TokenSource interface {
+	Token() (*Token, error)
+}
+
+// contextTokenSource is implemented by TokenSources
+// that can bind their own requests to a context.
+type contextTokenSource interface {
+	tokenWithContext(ctx context.Context) (*Token, error)
+}
+
+// tokenRefresher is implemented by TokenSources that can replace
+// an access token that was rejected by the API with a fresh one.
+type tokenRefresher interface {
+	refreshStale(ctx context.Context, staleAccessToken string) error
+}
+
+var (
+	errNilToken       = errors.New("expecting a non-nil token")
+	errNilTokenSource = errors.New("expecting a non-nil TokenSource")
+	errNoRefresh      = errors.New("token has no refresh_token")
+)
+
+// tokenExpiryDelta is how long before its expiry a
+// token is considered stale and proactively refreshed.
+const tokenExpiryDelta = 30 * time.Second
+
+// NewClientWithTokenSource creates a Client that
+// fetches its access token from ts for every request.
+func NewClientWithTokenSource(ts TokenSource) (*Client, error) {
+	if ts == nil {
+		return nil, errNilTokenSource
+	}
+	c := new(Client)
+	c.SetTokenSource(ts)
+	return c, nil
+}
+
+// SetTokenSource makes the client fetch its access
+// token from ts, replacing any previously set token.
+func (c *Client) SetTokenSource(ts TokenSource) {
+	c.mu.Lock()
+	c.ts = ts
+	c._authToken = ""
+	c.mu.Unlock()
+}
+
+func (c *Client) tokenSource() TokenSource {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ts
+}
+
+// NewClientFromToken creates a Client in token source mode: the
+// client keeps track of token's expiry, refreshes it before it
+// lapses and retries a request once if it is rejected with a 401.
+func NewClientFromToken(token *Token) (*Client, error) {
+	if token == nil {
+		return nil, errNilToken
+	}
+	c := new(Client)
+	c.SetToken(token)
+	return c, nil
+}
+
+// SetToken switches the client into token source mode using
+// token. Unless token.Expiry is set, token.ExpiresIn is measured
+// from the time of this call.
+func (c *Client) SetToken(token *Token) {
+	c.SetTokenSource(NewRefreshingTokenSource(c, token))
+}
+
+// Token returns the token that the client currently uses
+// in token source mode, or nil if it is not in that mode.
+func (c *Client) Token() *Token {
+	ts := c.tokenSource()
+	if ts == nil {
+		return nil
+	}
+	if rts, ok := ts.(*refreshingTokenSource); ok {
+		return rts.current()
+	}
+	tok, err := ts.Token()
+	if err != nil {
+		return nil
+	}
+	return tok
+}
+
+// expiry returns the time at which the token lapses if it was
+// issued at issuedAt, or the zero time if it never expires.
+func (t *Token) expiry(issuedAt time.Time) time.Time {
+	switch {
+	case t == nil:
+		return time.Time{}
+	case !t.Expiry.IsZero():
+		return t.Expiry
+	case t.ExpiresIn == 0:
+		return time.Time{}
+	default:
+		// A negative ExpiresIn is in the past, so the token is refreshed.
+		return issuedAt.Add(time.Duration(t.ExpiresIn * float64(time.Second)))
+	}
+}
+
+type staticTokenSource struct {
+	tok *Token
+}
+
+// StaticTokenSource returns a TokenSource that always returns
+// a copy of tok. The token is never refreshed.
+func StaticTokenSource(tok *Token) TokenSource {
+	return &staticTokenSource{tok: tok}
+}
+
+func (sts *staticTokenSource) Token() (*Token, error) {
+	if sts.tok == nil {
+		return nil, errNilToken
+	}
+	tok := new(Token)
+	*tok = *sts.tok
+	return tok, nil
+}
+
+type envTokenSource struct{}
+
+// EnvTokenSource returns a TokenSource that reads the access
+// token from the EnvBearerTokenKey environment variable on
+// every call, so that rotating the variable takes effect.
+func EnvTokenSource() TokenSource {
+	return envTokenSource{}
+}
+
+func (envTokenSource) Token() (*Token, error) {
+	accessToken := strings.TrimSpace(os.Getenv(EnvBearerTokenKey))
+	if accessToken == "" {
+		return nil, errMissingBearerTokenFromEnv
+	}
+	return &Token{AccessToken: accessToken, TokenType: "Bearer"}, nil
+}
+
+// refreshingTokenSource caches a token and uses
+// Client.RefreshToken to renew it before it expires.
+type refreshingTokenSource struct {
+	c *Client
+
+	// refreshMu serializes refreshes so that only
+	// one RefreshToken call is ever in flight.
+	refreshMu sync.Mutex
+
+	mu     sync.RWMutex
+	tok    *Token
+	expiry time.Time
+}
+
+var (
+	_ contextTokenSource = (*refreshingTokenSource)(nil)
+	_ tokenRefresher     = (*refreshingTokenSource)(nil)
+)
+
+// NewRefreshingTokenSource returns a TokenSource that caches tok and
+// uses c to refresh it shortly before it expires. Unless tok.Expiry
+// is set, tok.ExpiresIn is measured from the time of this call. The
+// returned tokens carry their Expiry and the seconds remaining until
+// then in ExpiresIn, which is negative once they expired.
+func NewRefreshingTokenSource(c *Client, tok *Token) TokenSource {
+	rts := &refreshingTokenSource{c: c}
+	rts.set(tok)
+	return rts
+}
+
+func (rts *refreshingTokenSource) set(tok *Token) {
+	rts.mu.Lock()
+	defer rts.mu.Unlock()
+	if tok == nil {
+		rts.tok, rts.expiry = nil, time.Time{}
+		return
+	}
+	cp := new(Token)
+	*cp = *tok
+	rts.tok, rts.expiry = cp, cp.expiry(time.Now())
+}
+
+func (rts *refreshingTokenSource) current() *Token {
+	rts.mu.RLock()
+	defer rts.mu.RUnlock()
+	if rts.tok == nil {
+		return nil
+	}
+	tok := new(Token)
+	*tok = *rts.tok
+	if !rts.expiry.IsZero() {
+		tok.ExpiresIn = time.Until(rts.expiry).Seconds()
+		tok.Expiry = rts.expiry
+	}
+	return tok
+}
+
+func (rts *refreshingTokenSource) Token() (*Token, error) {
+	return rts.tokenWithContext(context.Background())
+}
+
+// tokenWithContext refreshes the token if it is about to expire.
+// A failed refresh is only reported if the token has already lapsed.
+func (rts *refreshingTokenSource) tokenWithContext(ctx context.Context) (*Token, error) {
+	rts.mu.RLock()
+	tok, expiry := rts.tok, rts.expiry
+	rts.mu.RUnlock()
+	if tok == nil {
+		return nil, errNilToken
+	}
+
+	now := time.Now()
+	if expiry.IsZero() || tok.RefreshToken == "" || now.Add(tokenExpiryDelta).Before(expiry) {
+		return rts.current(), nil
+	}
+	if err := rts.refreshStale(ctx, tok.AccessToken); err != nil && !now.Before(expiry) {
+		return nil, err
+	}
+	return rts.current(), nil
+}
+
+// refreshStale replaces staleAccessToken with a freshly refreshed token.
+// If another caller already replaced staleAccessToken, it does nothing.
+func (rts *refreshingTokenSource) refreshStale(ctx context.Context, staleAccessToken string) error {
+	rts.refreshMu.Lock()
+	defer rts.refreshMu.Unlock()
+
+	rts.mu.RLock()
+	cur := rts.tok
+	rts.mu.RUnlock()
+	if cur == nil || cur.RefreshToken == "" {
+		return errNoRefresh
+	}
+	if cur.AccessToken != staleAccessToken {
+		return nil
+	}
+	refreshed, err := rts.c.RefreshTokenWithContext(ctx, cur.RefreshToken)
+	if err != nil {
+		return err
+	}
+	if refreshed.RefreshToken == "" {
+		refreshed.RefreshToken = cur.RefreshToken
+	}
+	rts.set(refreshed)
+	return nil
+}
+
+type oauth2TokenSource struct {
+	ts TokenSource
+}
+
+// OAuth2TokenSource adapts ts into an oauth2.TokenSource.
+func OAuth2TokenSource(ts TokenSource) oauth2.TokenSource {
+	return &oauth2TokenSource{ts: ts}
+}
+
+func (ots *oauth2TokenSource) Token() (*oauth2.Token, error) {
+	tok, err := ots.ts.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok == nil {
+		return nil, errNilToken
+	}
+	return tok.OAuth2Token(), nil
+}
+
+type fromOAuth2TokenSource struct {
+	ots oauth2.TokenSource
+}
+
+// FromOAuth2TokenSource adapts an oauth2.TokenSource into a TokenSource.
+func FromOAuth2TokenSource(ots oauth2.TokenSource) TokenSource {
+	return &fromOAuth2TokenSource{ots: ots}
+}
+
+func (fts *fromOAuth2TokenSource) Token() (*Token, error) {
+	otok, err := fts.ots.Token()
+	if err != nil {
+		return nil, err
+	}
+	if otok == nil {
+		return nil, errNilToken
+	}
+	return TokenFromOAuth2(otok), nil
+}
+
+// OAuth2Token converts t into an oauth2.Token whose
+// Expiry is t.ExpiresIn seconds from now.
+func (t *Token) OAuth2Token() *oauth2.Token {
+	otok := &oauth2.Token{
+		AccessToken:  t.AccessToken,
+		TokenType:    t.TokenType,
+		RefreshToken: t.RefreshToken,
+	}
+	if exp := t.expiry(time.Now()); !exp.IsZero() {
+		otok.Expiry = exp
+	}
+	if t.Permissions != "" {
+		otok = otok.WithExtra(map[string]interface{}{"permissions": t.Permissions})
+	}
+	return otok
+}
+
+// TokenFromOAuth2 converts otok into a Token that expires at
+// otok.Expiry, with ExpiresIn the number of seconds left until then.
+func TokenFromOAuth2(otok *oauth2.Token) *Token {
+	tok := &Token{
+		AccessToken:  otok.AccessToken,
+		TokenType:    otok.TokenType,
+		RefreshToken: otok.RefreshToken,
+	}
+	if !otok.Expiry.IsZero() {
+		tok.ExpiresIn = time.Until(otok.Expiry).Seconds()
+		tok.Expiry = otok.Expiry
+	}
+	if perms, ok := otok.Extra("permissions").(string); ok {
+		tok.Permissions = perms
+	}
+	return tok
+}
@@ -0,0 +1,150 @@
+package seedco_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/orijtech/seedco/v1"
+)
+
+func TestClientWithTokenSource(t *testing.T) {
+	client, err := seedco.NewClientWithTokenSource(seedco.StaticTokenSource(&seedco.Token{AccessToken: token1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: listBalancesRoute})
+
+	balances, err := client.ListBalances()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(balances) == 0 {
+		t.Errorf("expected balances")
+	}
+
+	if _, err := seedco.NewClientWithTokenSource(nil); err == nil {
+		t.Errorf("nil TokenSource: want non-nil error")
+	}
+}
+
+func TestEnvTokenSource(t *testing.T) {
+	defer os.Setenv(seedco.EnvBearerTokenKey, os.Getenv(seedco.EnvBearerTokenKey))
+
+	ts := seedco.EnvTokenSource()
+	os.Setenv(seedco.EnvBearerTokenKey, " ")
+	if tok, err := ts.Token(); err == nil {
+		t.Errorf("blank env: want non-nil error, got token: %+v", tok)
+	}
+
+	os.Setenv(seedco.EnvBearerTokenKey, "  from-env  ")
+	tok, err := ts.Token()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g, w := tok.AccessToken, "from-env"; g != w {
+		t.Errorf("accessToken: got=%q want=%q", g, w)
+	}
+}
+
+func TestOAuth2TokenSourceRoundTrip(t *testing.T) {
+	want := &seedco.Token{
+		AccessToken:  "access",
+		RefreshToken: "refresh",
+		TokenType:    "Bearer",
+		Permissions:  "public-api",
+		ExpiresIn:    3600,
+	}
+	otok, err := seedco.OAuth2TokenSource(seedco.StaticTokenSource(want)).Token()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g, w := otok.AccessToken, want.AccessToken; g != w {
+		t.Errorf("oauth2 accessToken: got=%q want=%q", g, w)
+	}
+	if left := time.Until(otok.Expiry); left <= 59*time.Minute || left > time.Hour {
+		t.Errorf("oauth2 expiry: %v left, want ~1h", left)
+	}
+
+	got, err := seedco.FromOAuth2TokenSource(oauth2.StaticTokenSource(otok)).Token()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.AccessToken != want.AccessToken || got.RefreshToken != want.RefreshToken ||
+		got.TokenType != want.TokenType || got.Permissions != want.Permissions {
+		t.Errorf("got: %+v\nwant:%+v", got, want)
+	}
+	if got.ExpiresIn <= 3500 || got.ExpiresIn > 3600 {
+		t.Errorf("expiresIn: got=%v want ~3600", got.ExpiresIn)
+	}
+}
+
+func TestRefreshingTokenSource(t *testing.T) {
+	lb := &tokenLifecycleBackend{accessToken: "access-1", refreshToken: "refresh-1"}
+	client, err := seedco.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetHTTPRoundTripper(lb)
+
+	ts := seedco.NewRefreshingTokenSource(client, &seedco.Token{
+		AccessToken:  "access-1",
+		RefreshToken: "refresh-1",
+		ExpiresIn:    1,
+	})
+	for i := 0; i < 3; i++ {
+		tok, err := ts.Token()
+		if err != nil {
+			t.Fatalf("#%d: unexpected error: %v", i, err)
+		}
+		if g, w := tok.AccessToken, "access-2"; g != w {
+			t.Errorf("#%d: accessToken: got=%q want=%q", i, g, w)
+		}
+		if g, w := tok.RefreshToken, "refresh-1"; g != w {
+			t.Errorf("#%d: refreshToken: got=%q want=%q", i, g, w)
+		}
+	}
+	if g, w := lb.refreshCount(), 1; g != w {
+		t.Errorf("refreshes: got=%d want=%d", g, w)
+	}
+}
+
+// An expired token keeps its expiry through Client.Token and
+// TokenFromOAuth2, and is refreshed before it is used again.
+func TestExpiredTokenRoundTrip(t *testing.T) {
+	expired := &seedco.Token{AccessToken: "access-1", RefreshToken: "refresh-1", ExpiresIn: -60}
+	first, err := seedco.NewClientFromToken(expired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tok := first.Token()
+	if tok.ExpiresIn >= 0 || !tok.Expiry.Before(time.Now()) {
+		t.Fatalf("Client.Token: got ExpiresIn=%v Expiry=%v want them in the past", tok.ExpiresIn, tok.Expiry)
+	}
+	tokens := []*seedco.Token{
+		tok,
+		seedco.TokenFromOAuth2(tok.OAuth2Token()),
+		// Only a negative ExpiresIn.
+		{AccessToken: "access-1", RefreshToken: "refresh-1", ExpiresIn: tok.ExpiresIn},
+	}
+
+	for i, tok := range tokens {
+		lb := &tokenLifecycleBackend{accessToken: "access-1", refreshToken: "refresh-1"}
+		client, err := seedco.NewClientFromToken(tok)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client.SetHTTPRoundTripper(lb)
+		if _, err := client.ListBalances(); err != nil {
+			t.Fatalf("#%d: unexpected error: %v", i, err)
+		}
+		if g, w := lb.refreshCount(), 1; g != w {
+			t.Errorf("#%d: refreshes: got=%d want=%d", i, g, w)
+		}
+		if g, w := lb.unauthorizedCount(), 0; g != w {
+			t.Errorf("#%d: unauthorized responses: got=%d want=%d", i, g, w)
+		}
+	}
+}
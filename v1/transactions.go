@@ -1,10 +1,14 @@
 package seedco
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -31,18 +35,21 @@ type SearchParams struct {
 
 var errAlreadyCanceled = errors.New("already canceled")
 
-func makeCanceler() (<-chan bool, func() error) {
+// makeCanceler derives a cancelable context from ctx. The returned
+// cancelFn reports errAlreadyCanceled on every call after the first,
+// while release only frees the context's resources.
+func makeCanceler(ctx context.Context) (_ context.Context, cancelFn func() error, release context.CancelFunc) {
 	var once sync.Once
-	cancelChan := make(chan bool, 1)
-	cancelFn := func() error {
+	ctx, cancel := context.WithCancel(ctx)
+	cancelFn = func() error {
 		var err error = errAlreadyCanceled
 		once.Do(func() {
 			err = nil
-			close(cancelChan)
+			cancel()
 		})
 		return err
 	}
-	return cancelChan, cancelFn
+	return ctx, cancelFn, cancel
 }
 
 type SearchResults struct {
@@ -58,6 +65,7 @@ type TransactionPage struct {
 
 type recvTransactions struct {
 	Transactions []*Transaction `json:"results,omitempty"`
+	Errors       []*Error       `json:"errors,omitempty"`
 }
 
 const defaultLimit = int(1000)
@@ -66,7 +74,22 @@ func (c *Client) SearchTransactions(sp *SearchParams) (*SearchResults, error) {
 	return c.ListTransactions(sp)
 }
 
+// SearchTransactionsWithContext is like SearchTransactions but
+// pagination stops once ctx is done.
+func (c *Client) SearchTransactionsWithContext(ctx context.Context, sp *SearchParams) (*SearchResults, error) {
+	return c.ListTransactionsWithContext(ctx, sp)
+}
+
 func (c *Client) ListTransactions(sp *SearchParams) (*SearchResults, error) {
+	return c.ListTransactionsWithContext(context.Background(), sp)
+}
+
+// ListTransactionsWithContext is like ListTransactions but every page
+// request is bound to ctx and pagination stops once ctx is done.
+func (c *Client) ListTransactionsWithContext(ctx context.Context, sp *SearchParams) (*SearchResults, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
 	if sp == nil {
 		sp = new(SearchParams)
 	}
@@ -76,63 +99,45 @@ func (c *Client) ListTransactions(sp *SearchParams) (*SearchResults, error) {
 		return maxPageNumber > 0 && pn >= maxPageNumber
 	}
 
-	cancelChan, cancelFn := makeCanceler()
+	ctx, cancelFn, release := makeCanceler(ctx)
 	pagesChan := make(chan *TransactionPage)
+	sendPage := func(tPage *TransactionPage) bool {
+		select {
+		case pagesChan <- tPage:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
 	go func() {
 		defer close(pagesChan)
+		defer release()
 
-		throttle := time.NewTicker(150 * time.Millisecond)
-		spc := new(SearchParams)
-		*spc = *sp
-		if spc.Limit <= 0 {
-			spc.Limit = defaultLimit
-		}
-		if spc.Offset <= 0 {
-			spc.Offset = 0
-		}
-
+		spc := sp.withDefaults()
 		pageNumber := int64(0)
 
 		for {
-			qv, err := otils.ToURLValues(spc)
-			tPage := &TransactionPage{
-				PageNumber: pageNumber,
-			}
+			transactions, err := c.fetchTransactionsPage(ctx, spc)
 			if err != nil {
-				tPage.Err = err
-				pagesChan <- tPage
+				if ctx.Err() == nil {
+					sendPage(&TransactionPage{PageNumber: pageNumber, Err: err})
+				}
 				return
 			}
-
-			fullURL := fmt.Sprintf("%s/public/transactions", baseURL)
-			if len(qv) > 0 {
-				fullURL = fmt.Sprintf("%s?%s", fullURL, qv.Encode())
-			}
-			req, err := http.NewRequest("GET", fullURL, nil)
-			if err != nil {
-				tPage.Err = err
-				pagesChan <- tPage
+			if len(transactions) == 0 {
 				return
 			}
-
-			blob, _, err := c.doAuthAndReq(req)
-			recvT := new(recvTransactions)
-			if err := json.Unmarshal(blob, recvT); err != nil {
-				tPage.Err = err
-				pagesChan <- tPage
-			} else if len(recvT.Transactions) > 0 {
-				tPage.Transactions = recvT.Transactions
-				pagesChan <- tPage
+			tPage := &TransactionPage{
+				PageNumber:   pageNumber,
+				Transactions: transactions,
 			}
-
-			pageNumber += 1
-			if len(tPage.Transactions) == 0 || exceedsMaxPage(pageNumber) {
+			if !sendPage(tPage) {
 				return
 			}
 
-			select {
-			case <-throttle.C:
-			case <-cancelChan:
+			pageNumber += 1
+			if exceedsMaxPage(pageNumber) {
 				return
 			}
 
@@ -148,12 +153,61 @@ func (c *Client) ListTransactions(sp *SearchParams) (*SearchResults, error) {
 	return sr, nil
 }
 
+// withDefaults returns a copy of sp with its
+// Limit and Offset set to usable values.
+func (sp *SearchParams) withDefaults() *SearchParams {
+	spc := new(SearchParams)
+	if sp != nil {
+		*spc = *sp
+	}
+	if spc.Limit <= 0 {
+		spc.Limit = defaultLimit
+	}
+	if spc.Offset <= 0 {
+		spc.Offset = 0
+	}
+	return spc
+}
+
+// fetchTransactionsPage retrieves the single page of
+// transactions that starts at sp.Offset.
+func (c *Client) fetchTransactionsPage(ctx context.Context, sp *SearchParams) ([]*Transaction, error) {
+	qv, err := otils.ToURLValues(sp)
+	if err != nil {
+		return nil, err
+	}
+	fullURL := fmt.Sprintf("%s/public/transactions", c.BaseURL())
+	if len(qv) > 0 {
+		fullURL = fmt.Sprintf("%s?%s", fullURL, qv.Encode())
+	}
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	blob, res, err := c.doAuthAndReq(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	recvT := new(recvTransactions)
+	if err := json.Unmarshal(blob, recvT); err != nil {
+		return nil, err
+	}
+	if err := flattenErrs(res, blob, recvT.Errors); err != nil {
+		return nil, err
+	}
+	return recvT.Transactions, nil
+}
+
 type Transaction struct {
 	ID string `json:"id,omitempty"`
 
 	CheckingAccountID string `json:"checking_account_id,omitempty"`
 
-	AmountCents float64 `json:"amount,omitempty"`
+	// AmountCents is positive for money leaving the account, such
+	// as card purchases and payments, and negative for money coming
+	// in, as in the transactions listed by the API. BalanceChange
+	// has the opposite sign.
+	AmountCents Cents `json:"amount,omitempty"`
 
 	// Status possible values are Settled, Pending.
 	Status Status `json:"status,omitempty"`
@@ -163,13 +217,142 @@ type Transaction struct {
 	// Date is when the transaction occured.
 	Date *time.Time `json:"date,omitempty"`
 
-	Category    string `json:"category,omitempty"`
-	Description string `json:"description,omitempty"`
+	Category    Category `json:"category,omitempty"`
+	Description string   `json:"description,omitempty"`
 
 	// Memo is the user entered memorandum for the transaction.
 	Memo string `json:"memo,omitempty"`
+
+	// ETag identifies this version of the transaction. It is only
+	// set by GetTransaction and UpdateTransaction, and can be passed
+	// as TransactionUpdate.IfMatch for optimistic concurrency.
+	ETag string `json:"-"`
+}
+
+var (
+	errBlankTransactionID = errors.New("transaction ID must be non-blank")
+	errNoTransaction      = errors.New("no transaction received")
+)
+
+// GetTransaction returns the transaction with the given ID.
+func (c *Client) GetTransaction(ctx context.Context, id string) (*Transaction, error) {
+	if id == "" {
+		return nil, errBlankTransactionID
+	}
+	fullURL := fmt.Sprintf("%s/public/transactions/%s", c.BaseURL(), url.PathEscape(id))
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	blob, res, err := c.doAuthAndReq(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return parseSingleTransaction(blob, res)
+}
+
+func parseSingleTransaction(blob []byte, res *http.Response) (*Transaction, error) {
+	recvT := new(recvTransactions)
+	if err := json.Unmarshal(blob, recvT); err != nil {
+		return nil, err
+	}
+	if err := flattenErrs(res, blob, recvT.Errors); err != nil {
+		return nil, err
+	}
+	if len(recvT.Transactions) == 0 || recvT.Transactions[0] == nil {
+		return nil, errNoTransaction
+	}
+	t := recvT.Transactions[0]
+	t.ETag = res.Header.Get("ETag")
+	return t, nil
+}
+
+// BatchError reports the IDs that failed in a batch lookup.
+type BatchError struct {
+	Errors map[string]error
+}
+
+var _ error = (*BatchError)(nil)
+
+func (be *BatchError) Error() string {
+	ids := make([]string, 0, len(be.Errors))
+	for id := range be.Errors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	msgs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", id, be.Errors[id]))
+	}
+	return strings.Join(msgs, "\n")
+}
+
+const defaultBatchConcurrency = 4
+
+// SetBatchConcurrency bounds how many requests batch lookups
+// such as GetTransactions have in flight at once. It defaults to 4.
+func (c *Client) SetBatchConcurrency(n int) {
+	c.mu.Lock()
+	c.batchConcurrency = n
+	c.mu.Unlock()
+}
+
+func (c *Client) batchConcurrencyLimit() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.batchConcurrency <= 0 {
+		return defaultBatchConcurrency
+	}
+	return c.batchConcurrency
+}
+
+// GetTransactions looks up the transactions with the given IDs concurrently,
+// with at most SetBatchConcurrency requests in flight. The returned slice is
+// aligned with ids. If any lookup failed its slot is nil and the returned
+// error is a *BatchError that maps the failed IDs to their errors.
+func (c *Client) GetTransactions(ctx context.Context, ids []string) ([]*Transaction, error) {
+	transactions := make([]*Transaction, len(ids))
+	errs := make([]error, len(ids))
+
+	sem := make(chan bool, c.batchConcurrencyLimit())
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		sem <- true
+		go func(i int, id string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			transactions[i], errs[i] = c.GetTransaction(ctx, id)
+		}(i, id)
+	}
+	wg.Wait()
+
+	be := &BatchError{Errors: make(map[string]error)}
+	for i, err := range errs {
+		if err != nil {
+			be.Errors[ids[i]] = err
+		}
+	}
+	if len(be.Errors) > 0 {
+		return transactions, be
+	}
+	return transactions, nil
+}
+
+// Amount returns the amount of the transaction as Money.
+func (t *Transaction) Amount() Money {
+	return USDollars(t.AmountCents)
+}
+
+// IsDebit reports whether the transaction took money out of the account.
+func (t *Transaction) IsDebit() bool {
+	return t.AmountCents > 0
 }
 
-type Attachment struct {
-	// TODO: Fill this in from seedco MGMT
+// BalanceChange returns how much the transaction changed the balance
+// of its account, negative for debits, or ErrOverflow.
+func (t *Transaction) BalanceChange() (Cents, error) {
+	return t.AmountCents.Mul(-1)
 }
@@ -1,14 +1,19 @@
 package seedco_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/orijtech/seedco/v1"
+	"github.com/orijtech/seedco/v1/seedcotest"
 )
 
 func TestListTransactions(t *testing.T) {
@@ -52,6 +57,130 @@ func TestListTransactions(t *testing.T) {
 	}
 }
 
+func TestListTransactionsWithContextCanceled(t *testing.T) {
+	client, err := seedco.NewClientWithToken(testToken1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetHTTPRoundTripper(&backend{route: listTransactionsRoute})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pres, err := client.ListTransactionsWithContext(ctx, &seedco.SearchParams{Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	page, ok := <-pres.PagesChan
+	if !ok || page.Err != nil {
+		t.Fatalf("first page: got=(%+v, %v) want a page without error", page, ok)
+	}
+	cancel()
+	for page := range pres.PagesChan {
+		if page.Err == nil {
+			t.Errorf("pageNumber: %d: unexpectedly received a page after cancelation", page.PageNumber)
+		}
+	}
+	if err := pres.Cancel(); err != nil {
+		t.Errorf("first Cancel: unexpected error: %v", err)
+	}
+	if err := pres.Cancel(); err == nil {
+		t.Errorf("second Cancel: want non-nil error")
+	}
+}
+
+func TestGetTransaction(t *testing.T) {
+	srv := seedcotest.NewUnstartedServer()
+	srv.AddTransactions(&seedco.Transaction{ID: "t1", Description: "Chipotle", AmountCents: 736})
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	txn, err := client.GetTransaction(ctx, "t1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g, w := txn.AmountCents, seedco.Cents(736); g != w {
+		t.Errorf("amount: got=%d want=%d", g, w)
+	}
+	if _, err := client.GetTransaction(ctx, "t2"); !errors.Is(err, seedco.ErrNotFound) {
+		t.Errorf("unknown transaction: got=(%v) want=(%v)", err, seedco.ErrNotFound)
+	}
+	if _, err := client.GetTransaction(ctx, ""); err == nil {
+		t.Errorf("blank ID: want non-nil error")
+	}
+}
+
+func TestGetTransactionsBoundedConcurrency(t *testing.T) {
+	srv := seedcotest.NewUnstartedServer()
+	var ids []string
+	for i := 0; i < 12; i++ {
+		id := fmt.Sprintf("t%d", i)
+		srv.AddTransactions(&seedco.Transaction{ID: id})
+		ids = append(ids, id)
+	}
+	ids = append(ids, "missing")
+
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cr := &concurrencyRecorder{RoundTripper: srv.Transport()}
+	client.SetHTTPRoundTripper(cr)
+	client.SetBatchConcurrency(3)
+
+	transactions, err := client.GetTransactions(context.Background(), ids)
+	var be *seedco.BatchError
+	if !errors.As(err, &be) {
+		t.Fatalf("got=(%v) want a *seedco.BatchError", err)
+	}
+	if g, w := len(be.Errors), 1; g != w || be.Errors["missing"] == nil {
+		t.Errorf("batch errors: got=%v want only %q", be.Errors, "missing")
+	}
+	for i, id := range ids[:12] {
+		if transactions[i] == nil || transactions[i].ID != id {
+			t.Errorf("#%d: got=%+v want ID %q", i, transactions[i], id)
+		}
+	}
+	if transactions[12] != nil {
+		t.Errorf("missing transaction: got=%+v want nil", transactions[12])
+	}
+	if g, w := cr.maxInFlight(), 3; g > w {
+		t.Errorf("in flight: got=%d want at most %d", g, w)
+	}
+}
+
+// concurrencyRecorder records the most requests that were in flight at once.
+type concurrencyRecorder struct {
+	http.RoundTripper
+
+	mu       sync.Mutex
+	inFlight int
+	max      int
+}
+
+func (cr *concurrencyRecorder) maxInFlight() int {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	return cr.max
+}
+
+func (cr *concurrencyRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	cr.mu.Lock()
+	cr.inFlight += 1
+	if cr.inFlight > cr.max {
+		cr.max = cr.inFlight
+	}
+	cr.mu.Unlock()
+	defer func() {
+		cr.mu.Lock()
+		cr.inFlight -= 1
+		cr.mu.Unlock()
+	}()
+	time.Sleep(10 * time.Millisecond)
+	return cr.RoundTripper.RoundTrip(req)
+}
+
 func listTransactionsRoundTrip(req *http.Request) (*http.Response, error) {
 	_, badRes, err := ensureBearerTokenAuthd(req)
 	if badRes != nil || err != nil {
@@ -0,0 +1,71 @@
+package seedco
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// TransactionUpdate is a partial update of a transaction: only
+// the fields that are set are changed. Use SetMemo("") to clear
+// the memo.
+type TransactionUpdate struct {
+	Memo     *string   `json:"memo,omitempty"`
+	Category *Category `json:"category,omitempty"`
+
+	// IfMatch, if set to the ETag of a previously fetched transaction,
+	// makes the update fail with an error matching ErrConflict if the
+	// transaction was changed by someone else in the meantime.
+	IfMatch string `json:"-"`
+}
+
+func (tu *TransactionUpdate) SetMemo(memo string) *TransactionUpdate {
+	tu.Memo = &memo
+	return tu
+}
+
+func (tu *TransactionUpdate) SetCategory(c Category) *TransactionUpdate {
+	tu.Category = &c
+	return tu
+}
+
+var (
+	errEmptyUpdate   = errors.New("update does not change any field")
+	errBlankCategory = errors.New("category must be non-blank")
+)
+
+// UpdateTransaction applies update to the transaction with the
+// given ID and returns the transaction as updated.
+func (c *Client) UpdateTransaction(ctx context.Context, id string, update TransactionUpdate) (*Transaction, error) {
+	if id == "" {
+		return nil, errBlankTransactionID
+	}
+	if update.Memo == nil && update.Category == nil {
+		return nil, errEmptyUpdate
+	}
+	if update.Category != nil && *update.Category == "" {
+		return nil, errBlankCategory
+	}
+	blob, err := json.Marshal(update)
+	if err != nil {
+		return nil, err
+	}
+	fullURL := fmt.Sprintf("%s/public/transactions/%s", c.BaseURL(), url.PathEscape(id))
+	req, err := http.NewRequest("PATCH", fullURL, bytes.NewReader(blob))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if update.IfMatch != "" {
+		req.Header.Set("If-Match", update.IfMatch)
+	}
+	blob, res, err := c.doAuthAndReq(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return parseSingleTransaction(blob, res)
+}
@@ -0,0 +1,99 @@
+package seedco_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/orijtech/seedco/v1"
+	"github.com/orijtech/seedco/v1/seedcotest"
+)
+
+func TestUpdateTransaction(t *testing.T) {
+	srv := seedcotest.NewUnstartedServer()
+	srv.AddTransactions(&seedco.Transaction{
+		ID:          "t1",
+		Description: "Uber",
+		Category:    "Uber",
+		Memo:        "Metreon sites",
+	})
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	// Only the category changes, the memo is left as is.
+	update := new(seedco.TransactionUpdate).SetCategory(seedco.CategoryTravel)
+	txn, err := client.UpdateTransaction(ctx, "t1", *update)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g, w := txn.Category, seedco.CategoryTravel; g != w {
+		t.Errorf("category: got=%q want=%q", g, w)
+	}
+	if g, w := txn.Memo, "Metreon sites"; g != w {
+		t.Errorf("memo: got=%q want=%q", g, w)
+	}
+
+	// An empty memo is sent and clears it.
+	txn, err = client.UpdateTransaction(ctx, "t1", *new(seedco.TransactionUpdate).SetMemo(""))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if txn.Memo != "" {
+		t.Errorf("memo: got=%q want it cleared", txn.Memo)
+	}
+	if g, w := txn.Category, seedco.CategoryTravel; g != w {
+		t.Errorf("category: got=%q want=%q", g, w)
+	}
+
+	if _, err := client.UpdateTransaction(ctx, "t1", seedco.TransactionUpdate{}); err == nil {
+		t.Errorf("empty update: want non-nil error")
+	}
+	if _, err := client.UpdateTransaction(ctx, "t2", *update); !errors.Is(err, seedco.ErrNotFound) {
+		t.Errorf("unknown transaction: got=(%v) want=(%v)", err, seedco.ErrNotFound)
+	}
+}
+
+func TestUpdateTransactionIfMatch(t *testing.T) {
+	srv := seedcotest.NewUnstartedServer()
+	srv.AddTransactions(&seedco.Transaction{ID: "t1", Description: "Duvet"})
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	fetched, err := client.GetTransaction(ctx, "t1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fetched.ETag == "" {
+		t.Fatal("expected an ETag")
+	}
+
+	// A concurrent editor gets there first.
+	if _, err := client.UpdateTransaction(ctx, "t1", *new(seedco.TransactionUpdate).SetMemo("theirs")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	update := new(seedco.TransactionUpdate).SetMemo("ours")
+	update.IfMatch = fetched.ETag
+	if _, err := client.UpdateTransaction(ctx, "t1", *update); !errors.Is(err, seedco.ErrConflict) {
+		t.Fatalf("stale ETag: got=(%v) want=(%v)", err, seedco.ErrConflict)
+	}
+
+	refetched, err := client.GetTransaction(ctx, "t1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	update.IfMatch = refetched.ETag
+	txn, err := client.UpdateTransaction(ctx, "t1", *update)
+	if err != nil {
+		t.Fatalf("fresh ETag: unexpected error: %v", err)
+	}
+	if g, w := txn.Memo, "ours"; g != w {
+		t.Errorf("memo: got=%q want=%q", g, w)
+	}
+}
@@ -1,11 +1,11 @@
 package seedco
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
-	"strings"
 	"time"
 )
 
@@ -14,12 +14,18 @@ var (
 )
 
 func (c *Client) APIVersion() (*APIVersion, error) {
-	fullURL := fmt.Sprintf("%s/public/api/client-version", baseURL)
+	return c.APIVersionWithContext(context.Background())
+}
+
+// APIVersionWithContext is like APIVersion but
+// the request is bound to the lifetime of ctx.
+func (c *Client) APIVersionWithContext(ctx context.Context) (*APIVersion, error) {
+	fullURL := fmt.Sprintf("%s/public/api/client-version", c.BaseURL())
 	req, err := http.NewRequest("POST", fullURL, nil)
 	if err != nil {
 		return nil, err
 	}
-	blob, _, err := c.doAuthAndReq(req)
+	blob, res, err := c.doAuthAndReq(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -27,7 +33,7 @@ func (c *Client) APIVersion() (*APIVersion, error) {
 	if err := json.Unmarshal(blob, avr); err != nil {
 		return nil, err
 	}
-	if err := flattenErrs(avr.Errors); err != nil {
+	if err := flattenErrs(res, blob, avr.Errors); err != nil {
 		return nil, err
 	}
 	if len(avr.Results) == 0 {
@@ -36,20 +42,22 @@ func (c *Client) APIVersion() (*APIVersion, error) {
 	return avr.Results[0], nil
 }
 
-func flattenErrs(errsList []*Error) error {
-	if len(errsList) == 0 {
-		return nil
-	}
-	var strErrs []string
+// flattenErrs returns an *APIError for the non-blank errors in
+// errsList, reported in blob, the body of res, or nil if there
+// are none.
+func flattenErrs(res *http.Response, blob []byte, errsList []*Error) error {
+	var nonBlank []*Error
 	for _, err := range errsList {
-		if s := err.Error(); s != "" {
-			strErrs = append(strErrs, s)
+		if err.Error() != "" {
+			nonBlank = append(nonBlank, err)
 		}
 	}
-	if len(strErrs) == 0 {
+	if len(nonBlank) == 0 {
 		return nil
 	}
-	return errors.New(strings.Join(strErrs, "\n"))
+	ae := newAPIError(res, blob)
+	ae.Errors = nonBlank
+	return ae
 }
 
 type apiVersionResponse struct {
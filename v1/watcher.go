@@ -0,0 +1,366 @@
+package seedco
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// WatchEventKind describes a change that a Watcher noticed.
+type WatchEventKind int
+
+const (
+	WatchNewTransaction WatchEventKind = iota + 1
+	WatchTransactionSettled
+	WatchAmountChanged
+	WatchTransactionRemoved
+	WatchThresholdCrossed
+)
+
+func (k WatchEventKind) String() string {
+	switch k {
+	case WatchNewTransaction:
+		return "new transaction"
+	case WatchTransactionSettled:
+		return "transaction settled"
+	case WatchAmountChanged:
+		return "amount changed"
+	case WatchTransactionRemoved:
+		return "transaction removed"
+	case WatchThresholdCrossed:
+		return "threshold crossed"
+	default:
+		return "unknown"
+	}
+}
+
+type WatchEvent struct {
+	Kind WatchEventKind
+
+	// Transaction and Previous are set for the transaction
+	// events, as for the SyncEvent that they derive from.
+	Transaction *Transaction
+	Previous    *Transaction
+
+	// Balance, PreviousBalance and Threshold are set for
+	// WatchThresholdCrossed. The balance fell below the threshold
+	// if Balance.TotalAvailable is less than Threshold.Amount,
+	// otherwise it rose to or above it.
+	Balance         *Balance
+	PreviousBalance *Balance
+	Threshold       *BalanceThreshold
+}
+
+// BalanceThreshold is crossed whenever the TotalAvailable
+// of a balance moves from below Amount to at least Amount
+// or the other way around.
+type BalanceThreshold struct {
+	// CheckingAccountID limits the threshold to one account.
+	// If blank, the threshold applies to every account.
+	CheckingAccountID string
+	Amount            Cents
+}
+
+func (bt *BalanceThreshold) crossedBy(prev, cur *Balance) bool {
+	if bt.CheckingAccountID != "" && bt.CheckingAccountID != cur.CheckingAccountID {
+		return false
+	}
+	return (prev.TotalAvailable < bt.Amount) != (cur.TotalAvailable < bt.Amount)
+}
+
+// Watcher polls for changes to balances and transactions, for
+// when webhooks cannot be received. Transactions are polled
+// incrementally with a Syncer. The first poll only records
+// the current state, later polls report what changed since.
+type Watcher struct {
+	Client *Client
+
+	// Params, Overlap, Store and Key configure the
+	// underlying Syncer. Store defaults to a MemoryCursorStore;
+	// with a persistent Store, a restarted Watcher reports
+	// the changes made while it was down.
+	Params  SearchParams
+	Overlap time.Duration
+	Store   CursorStore
+	Key     string
+
+	// TransactionInterval and BalanceInterval are the pauses
+	// between polls. They default to a minute; a negative
+	// interval disables polling for that resource.
+	TransactionInterval time.Duration
+	BalanceInterval     time.Duration
+
+	Thresholds []BalanceThreshold
+
+	// OnError, if set, is invoked with the errors of failed polls.
+	// Polling carries on after them; the changes are picked up
+	// by the next successful poll.
+	OnError func(error)
+
+	mu        sync.Mutex
+	running   bool
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce *sync.Once
+	store     CursorStore
+	synced    bool
+	balances  map[string]*Balance
+}
+
+const defaultWatchInterval = time.Minute
+
+var (
+	errNilWatchClient = errors.New("watcher: expecting a non-nil Client")
+	errWatcherRunning = errors.New("watcher: already running")
+	errWatcherClosed  = errors.New("watcher: closed")
+)
+
+// Run polls until ctx is done or Close is called, passing each change
+// to fn, and then returns nil. Events are delivered one at a time. If
+// fn returns an error, Run stops and returns it; the changes of that
+// poll are reported again by the next Run.
+func (w *Watcher) Run(ctx context.Context, fn func(*WatchEvent) error) error {
+	stop, done, err := w.start()
+	if err != nil {
+		return err
+	}
+	return w.run(ctx, fn, stop, done)
+}
+
+// start marks the watcher as running, so that Close stops it
+// even before run is entered.
+func (w *Watcher) start() (stop, done chan struct{}, err error) {
+	if w.Client == nil {
+		return nil, nil, errNilWatchClient
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.running {
+		return nil, nil, errWatcherRunning
+	}
+	w.running = true
+	w.stop, w.done, w.closeOnce = make(chan struct{}), make(chan struct{}), new(sync.Once)
+	if w.store == nil {
+		w.store = w.Store
+		if w.store == nil {
+			w.store = NewMemoryCursorStore()
+		}
+	}
+	return w.stop, w.done, nil
+}
+
+func (w *Watcher) run(ctx context.Context, fn func(*WatchEvent) error, stop, done chan struct{}) error {
+	defer func() {
+		w.mu.Lock()
+		w.running = false
+		w.mu.Unlock()
+		close(done)
+	}()
+
+	// Close may have been called between start and run.
+	select {
+	case <-stop:
+		return nil
+	default:
+	}
+
+	txnTick, stopTxn := watchTicker(w.TransactionInterval)
+	defer stopTxn()
+	balTick, stopBal := watchTicker(w.BalanceInterval)
+	defer stopBal()
+
+	if txnTick != nil {
+		if err := w.pollTransactions(ctx, fn); err != nil {
+			return err
+		}
+	}
+	if balTick != nil {
+		if err := w.pollBalances(ctx, fn); err != nil {
+			return err
+		}
+	}
+	for {
+		var err error
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-stop:
+			return nil
+		case <-txnTick:
+			err = w.pollTransactions(ctx, fn)
+		case <-balTick:
+			err = w.pollBalances(ctx, fn)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// watchTicker returns a nil channel, which never fires,
+// for a negative interval.
+func watchTicker(interval time.Duration) (<-chan time.Time, func()) {
+	if interval < 0 {
+		return nil, func() {}
+	}
+	if interval == 0 {
+		interval = defaultWatchInterval
+	}
+	ticker := time.NewTicker(interval)
+	return ticker.C, ticker.Stop
+}
+
+// Events runs the watcher in the background and delivers its events
+// on the returned channel. The channel is closed once ctx is done or
+// Close was called. Errors that stop the watcher go to OnError.
+func (w *Watcher) Events(ctx context.Context) <-chan *WatchEvent {
+	eventsChan := make(chan *WatchEvent)
+	stop, done, err := w.start()
+	if err != nil {
+		w.reportError(err)
+		close(eventsChan)
+		return eventsChan
+	}
+	go func() {
+		defer close(eventsChan)
+		err := w.run(ctx, func(ev *WatchEvent) error {
+			select {
+			case eventsChan <- ev:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-stop:
+				return errWatcherClosed
+			}
+		}, stop, done)
+		if err != nil && err != errWatcherClosed && ctx.Err() == nil {
+			w.reportError(err)
+		}
+	}()
+	return eventsChan
+}
+
+// Close stops a running watcher once its current poll is
+// done and waits for Run to return. It is a no-op if the
+// watcher is not running.
+func (w *Watcher) Close() error {
+	w.mu.Lock()
+	if !w.running {
+		w.mu.Unlock()
+		return nil
+	}
+	stop, done, closeOnce := w.stop, w.done, w.closeOnce
+	w.mu.Unlock()
+
+	closeOnce.Do(func() { close(stop) })
+	<-done
+	return nil
+}
+
+func (w *Watcher) reportError(err error) {
+	if w.OnError != nil {
+		w.OnError(err)
+	}
+}
+
+// handlerError distinguishes the errors of the event
+// callback from those of the poll itself.
+type handlerError struct{ err error }
+
+func (he *handlerError) Error() string { return he.err.Error() }
+
+func (w *Watcher) pollTransactions(ctx context.Context, fn func(*WatchEvent) error) error {
+	baseline := false
+	if !w.synced {
+		cur, err := w.store.LoadCursor(ctx, w.Key)
+		if err != nil {
+			w.reportError(err)
+			return nil
+		}
+		baseline = cur == nil
+	}
+
+	syncer := &Syncer{
+		Client:  w.Client,
+		Store:   w.store,
+		Key:     w.Key,
+		Params:  w.Params,
+		Overlap: w.Overlap,
+	}
+	_, err := syncer.Sync(ctx, func(sev *SyncEvent) error {
+		if baseline {
+			return nil
+		}
+		for _, ev := range watchEventsOf(sev) {
+			if err := fn(ev); err != nil {
+				return &handlerError{err: err}
+			}
+		}
+		return nil
+	})
+	if he, ok := err.(*handlerError); ok {
+		return he.err
+	}
+	if err != nil {
+		if ctx.Err() == nil {
+			w.reportError(err)
+		}
+		return nil
+	}
+	w.synced = true
+	return nil
+}
+
+func watchEventsOf(sev *SyncEvent) []*WatchEvent {
+	switch sev.Kind {
+	case TransactionAdded:
+		return []*WatchEvent{{Kind: WatchNewTransaction, Transaction: sev.Transaction}}
+	case TransactionRemoved:
+		return []*WatchEvent{{Kind: WatchTransactionRemoved, Transaction: sev.Transaction}}
+	}
+
+	var events []*WatchEvent
+	prev, cur := sev.Previous, sev.Transaction
+	if prev.Status == Pending && cur.Status == Settled {
+		events = append(events, &WatchEvent{Kind: WatchTransactionSettled, Transaction: cur, Previous: prev})
+	}
+	if prev.AmountCents != cur.AmountCents {
+		events = append(events, &WatchEvent{Kind: WatchAmountChanged, Transaction: cur, Previous: prev})
+	}
+	return events
+}
+
+func (w *Watcher) pollBalances(ctx context.Context, fn func(*WatchEvent) error) error {
+	balances, err := w.Client.ListBalancesWithContext(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			w.reportError(err)
+		}
+		return nil
+	}
+
+	next := make(map[string]*Balance, len(balances))
+	for _, cur := range balances {
+		if cur == nil {
+			continue
+		}
+		next[cur.CheckingAccountID] = cur
+		prev := w.balances[cur.CheckingAccountID]
+		if prev == nil {
+			continue
+		}
+		for i := range w.Thresholds {
+			th := &w.Thresholds[i]
+			if !th.crossedBy(prev, cur) {
+				continue
+			}
+			ev := &WatchEvent{Kind: WatchThresholdCrossed, Balance: cur, PreviousBalance: prev, Threshold: th}
+			if err := fn(ev); err != nil {
+				return err
+			}
+		}
+	}
+	w.balances = next
+	return nil
+}
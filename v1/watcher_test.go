@@ -0,0 +1,164 @@
+package seedco_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/orijtech/seedco/v1"
+	"github.com/orijtech/seedco/v1/seedcotest"
+)
+
+func TestWatcherEvents(t *testing.T) {
+	now := time.Now().UTC()
+	srv := seedcotest.NewUnstartedServer()
+	srv.AddTransactions(
+		&seedco.Transaction{ID: "t1", Status: seedco.Pending, AmountCents: -1500, Date: &now},
+		&seedco.Transaction{ID: "t2", Status: seedco.Settled, AmountCents: -300, Date: &now},
+	)
+	srv.SetBalances(&seedco.Balance{CheckingAccountID: "a1", TotalAvailable: 100000})
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := &seedco.Watcher{
+		Client:              client,
+		TransactionInterval: 5 * time.Millisecond,
+		BalanceInterval:     5 * time.Millisecond,
+		Thresholds:          []seedco.BalanceThreshold{{Amount: 50000}},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	eventsChan := w.Events(ctx)
+
+	// Let the first polls record the baseline.
+	for srv.Requests(seedcotest.BalanceRoute) < 2 || srv.Requests(seedcotest.TransactionsRoute) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	srv.AddTransactions(
+		&seedco.Transaction{ID: "t1", Status: seedco.Settled, AmountCents: -1600, Date: &now},
+		&seedco.Transaction{ID: "t3", Status: seedco.Pending, AmountCents: -700, Date: &now},
+	)
+	srv.SetBalances(&seedco.Balance{CheckingAccountID: "a1", TotalAvailable: 40000})
+
+	var got []string
+	for len(got) < 4 {
+		ev, ok := <-eventsChan
+		if !ok {
+			t.Fatalf("events closed early, got %q", got)
+		}
+		if ev.Kind == seedco.WatchThresholdCrossed {
+			got = append(got, ev.Kind.String()+":"+ev.Balance.CheckingAccountID)
+		} else {
+			got = append(got, ev.Kind.String()+":"+ev.Transaction.ID)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close: unexpected error: %v", err)
+	}
+	if ev, ok := <-eventsChan; ok {
+		t.Errorf("after close: got event %+v", ev)
+	}
+
+	sort.Strings(got)
+	want := []string{"amount changed:t1", "new transaction:t3", "threshold crossed:a1", "transaction settled:t1"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("#%d: got=%q want=%q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestWatcherCloseRightAfterEvents(t *testing.T) {
+	srv := seedcotest.NewUnstartedServer()
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := &seedco.Watcher{Client: client, TransactionInterval: time.Millisecond, BalanceInterval: time.Millisecond}
+	eventsChan := w.Events(context.Background())
+	if err := w.Close(); err != nil {
+		t.Fatalf("close: unexpected error: %v", err)
+	}
+	select {
+	case _, ok := <-eventsChan:
+		if ok {
+			t.Errorf("after close: got an event")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("events still open after close")
+	}
+}
+
+func TestWatcherRun(t *testing.T) {
+	srv := seedcotest.NewUnstartedServer()
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var pollErrs []error
+	w := &seedco.Watcher{
+		Client:              client,
+		TransactionInterval: -1,
+		BalanceInterval:     5 * time.Millisecond,
+		Thresholds:          []seedco.BalanceThreshold{{CheckingAccountID: "a1", Amount: 100}},
+		OnError: func(err error) {
+			mu.Lock()
+			pollErrs = append(pollErrs, err)
+			mu.Unlock()
+		},
+	}
+
+	// Failed polls are reported and polling carries on.
+	srv.FailNext(seedcotest.BalanceRoute, http.StatusBadGateway)
+	srv.SetBalances(&seedco.Balance{CheckingAccountID: "a1", TotalAvailable: 50})
+	errStop := errors.New("stop")
+	done := make(chan error)
+	go func() {
+		done <- w.Run(context.Background(), func(ev *seedco.WatchEvent) error {
+			return errStop
+		})
+	}()
+	for srv.Requests(seedcotest.BalanceRoute) < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	if err := w.Run(context.Background(), nil); err == nil {
+		t.Errorf("concurrent Run: want non-nil error")
+	}
+
+	// The callback's error stops the watcher.
+	srv.SetBalances(&seedco.Balance{CheckingAccountID: "a1", TotalAvailable: 150})
+	select {
+	case err := <-done:
+		if err != errStop {
+			t.Errorf("run: got=(%v) want=(%v)", err, errStop)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watcher did not stop")
+	}
+	if srv.Requests(seedcotest.TransactionsRoute) != 0 {
+		t.Errorf("transactions were polled despite a negative interval")
+	}
+	mu.Lock()
+	if len(pollErrs) != 1 || !errors.Is(pollErrs[0], seedco.ErrServer) {
+		t.Errorf("poll errors: got=%v want one server error", pollErrs)
+	}
+	mu.Unlock()
+
+	// Canceling the context stops the watcher gracefully.
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		done <- w.Run(ctx, func(ev *seedco.WatchEvent) error { return nil })
+	}()
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("canceled run: unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,267 @@
+package seedco
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventTransactionCreated EventType = "transaction.created"
+	EventTransactionSettled EventType = "transaction.settled"
+	EventBalanceChanged     EventType = "balance.changed"
+	EventPaymentStatus      EventType = "payment.status_changed"
+)
+
+// Event is a webhook delivery as sent by Seed.
+// Data holds the resource that the event is about.
+type Event struct {
+	ID        string          `json:"id"`
+	Type      EventType       `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// TransactionEvent is delivered for EventTransactionCreated
+// and EventTransactionSettled.
+type TransactionEvent struct {
+	*Event
+	Transaction *Transaction
+}
+
+// BalanceEvent is delivered for EventBalanceChanged.
+type BalanceEvent struct {
+	*Event
+	Balance *Balance
+}
+
+// PaymentEvent is delivered for EventPaymentStatus.
+type PaymentEvent struct {
+	*Event
+	Payment *Payment
+
+	// PreviousStatus is the status that the payment changed from.
+	PreviousStatus PaymentStatus
+}
+
+type paymentEventData struct {
+	*Payment
+	PreviousStatus PaymentStatus `json:"previous_status,omitempty"`
+}
+
+// WebhookSignatureHeader carries the signature of a webhook delivery:
+//
+//	Seed-Signature: t=<unix seconds>,v1=<hex HMAC-SHA256>
+//
+// where the HMAC is keyed with the webhook secret and computed
+// over the timestamp, a period and the raw request body.
+const WebhookSignatureHeader = "Seed-Signature"
+
+// DefaultWebhookTolerance is how old a delivery may be before
+// WebhookHandler rejects it as a possible replay.
+const DefaultWebhookTolerance = 5 * time.Minute
+
+var (
+	ErrInvalidSignature = errors.New("seedco: invalid webhook signature")
+	ErrStaleWebhook     = errors.New("seedco: webhook timestamp outside of tolerance")
+
+	errBlankWebhookSecret = errors.New("webhook secret must be non-blank")
+	errMalformedEvent     = errors.New("malformed event data")
+)
+
+// SignWebhook returns the WebhookSignatureHeader value of body
+// delivered at t, as computed by Seed.
+func SignWebhook(secret []byte, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", ts, hex.EncodeToString(webhookMAC(secret, ts, body)))
+}
+
+func webhookMAC(secret []byte, ts string, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// VerifyWebhookSignature checks that header, the WebhookSignatureHeader
+// value of body, was signed with secret no more than tolerance before
+// now. Any of several v1 signatures may match, to allow rotating secrets.
+func VerifyWebhookSignature(secret []byte, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var ts string
+	var sigs [][]byte
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			ts = kv[1]
+		case "v1":
+			if sig, err := hex.DecodeString(kv[1]); err == nil {
+				sigs = append(sigs, sig)
+			}
+		}
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || len(sigs) == 0 {
+		return ErrInvalidSignature
+	}
+
+	want := webhookMAC(secret, ts, body)
+	valid := false
+	for _, sig := range sigs {
+		if hmac.Equal(sig, want) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return ErrInvalidSignature
+	}
+
+	if tolerance > 0 {
+		age := now.Sub(time.Unix(unix, 0))
+		if age > tolerance || age < -tolerance {
+			return ErrStaleWebhook
+		}
+	}
+	return nil
+}
+
+// WebhookHandler is an http.Handler that receives Seed webhook
+// deliveries, verifies their signature and passes them to the
+// callback registered for their type. A delivery is acknowledged
+// with a 200 only if its callback returned nil, otherwise Seed
+// delivers it again later. Deliveries of types without a callback
+// are acknowledged and dropped.
+type WebhookHandler struct {
+	// Secret is the signing secret of the webhook endpoint.
+	Secret []byte
+
+	// Tolerance is the maximum age of a delivery. It defaults
+	// to DefaultWebhookTolerance; a negative value disables the check.
+	Tolerance time.Duration
+
+	OnTransactionCreated func(context.Context, *TransactionEvent) error
+	OnTransactionSettled func(context.Context, *TransactionEvent) error
+	OnBalanceChanged     func(context.Context, *BalanceEvent) error
+	OnPaymentStatus      func(context.Context, *PaymentEvent) error
+
+	// OnEvent, if set, is invoked with events of any other type.
+	OnEvent func(context.Context, *Event) error
+
+	// OnError, if set, is invoked with the reason that a delivery
+	// was rejected. Seed is only told the HTTP status, so that
+	// errors of the callbacks do not leak out of the handler.
+	OnError func(*http.Request, error)
+}
+
+var _ http.Handler = (*WebhookHandler)(nil)
+
+// maxWebhookSize bounds the body of a delivery.
+const maxWebhookSize = 1 << 20
+
+func (wh *WebhookHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "POST" {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if len(wh.Secret) == 0 {
+		wh.reject(w, req, http.StatusInternalServerError, errBlankWebhookSecret)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, maxWebhookSize))
+	if err != nil {
+		wh.reject(w, req, http.StatusBadRequest, err)
+		return
+	}
+	tolerance := wh.Tolerance
+	if tolerance == 0 {
+		tolerance = DefaultWebhookTolerance
+	}
+	if err := VerifyWebhookSignature(wh.Secret, req.Header.Get(WebhookSignatureHeader), body, tolerance, time.Now()); err != nil {
+		wh.reject(w, req, http.StatusUnauthorized, err)
+		return
+	}
+
+	ev := new(Event)
+	if err := json.Unmarshal(body, ev); err != nil {
+		wh.reject(w, req, http.StatusBadRequest, err)
+		return
+	}
+	if err := wh.dispatch(req.Context(), ev); err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, errMalformedEvent) {
+			code = http.StatusBadRequest
+		}
+		wh.reject(w, req, code, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// reject replies to req with the text of code alone
+// and passes err on to OnError.
+func (wh *WebhookHandler) reject(w http.ResponseWriter, req *http.Request, code int, err error) {
+	if wh.OnError != nil {
+		wh.OnError(req, err)
+	}
+	http.Error(w, http.StatusText(code), code)
+}
+
+func (wh *WebhookHandler) dispatch(ctx context.Context, ev *Event) error {
+	switch ev.Type {
+	case EventTransactionCreated, EventTransactionSettled:
+		fn := wh.OnTransactionCreated
+		if ev.Type == EventTransactionSettled {
+			fn = wh.OnTransactionSettled
+		}
+		if fn == nil {
+			return nil
+		}
+		t := new(Transaction)
+		if err := json.Unmarshal(ev.Data, t); err != nil {
+			return fmt.Errorf("%w: %v", errMalformedEvent, err)
+		}
+		return fn(ctx, &TransactionEvent{Event: ev, Transaction: t})
+
+	case EventBalanceChanged:
+		if wh.OnBalanceChanged == nil {
+			return nil
+		}
+		b := new(Balance)
+		if err := json.Unmarshal(ev.Data, b); err != nil {
+			return fmt.Errorf("%w: %v", errMalformedEvent, err)
+		}
+		return wh.OnBalanceChanged(ctx, &BalanceEvent{Event: ev, Balance: b})
+
+	case EventPaymentStatus:
+		if wh.OnPaymentStatus == nil {
+			return nil
+		}
+		data := &paymentEventData{Payment: new(Payment)}
+		if err := json.Unmarshal(ev.Data, data); err != nil {
+			return fmt.Errorf("%w: %v", errMalformedEvent, err)
+		}
+		return wh.OnPaymentStatus(ctx, &PaymentEvent{Event: ev, Payment: data.Payment, PreviousStatus: data.PreviousStatus})
+
+	default:
+		if wh.OnEvent == nil {
+			return nil
+		}
+		return wh.OnEvent(ctx, ev)
+	}
+}
@@ -0,0 +1,184 @@
+package seedco_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orijtech/seedco/v1"
+	"github.com/orijtech/seedco/v1/seedcotest"
+)
+
+var webhookSecret = []byte("whsec_test")
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	body := []byte(`{"id":"ev1"}`)
+	now := time.Unix(1520000000, 0)
+	valid := seedco.SignWebhook(webhookSecret, now, body)
+	other := seedco.SignWebhook([]byte("whsec_other"), now, body)
+
+	tests := [...]struct {
+		header  string
+		body    []byte
+		now     time.Time
+		wantErr error
+	}{
+		0: {header: valid, body: body, now: now},
+		1: {header: valid, body: body, now: now.Add(4 * time.Minute)},
+		2: {header: valid, body: body, now: now.Add(6 * time.Minute), wantErr: seedco.ErrStaleWebhook},
+		3: {header: valid, body: []byte(`{"id":"ev2"}`), now: now, wantErr: seedco.ErrInvalidSignature},
+		4: {header: other, body: body, now: now, wantErr: seedco.ErrInvalidSignature},
+		5: {header: "", body: body, now: now, wantErr: seedco.ErrInvalidSignature},
+		6: {
+			// One of several signatures matches during secret rotation.
+			header: other + "," + valid[len("t=1520000000,"):],
+			body:   body,
+			now:    now,
+		},
+	}
+
+	for i, tt := range tests {
+		err := seedco.VerifyWebhookSignature(webhookSecret, tt.header, tt.body, 5*time.Minute, tt.now)
+		if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+			t.Errorf("#%d: got=(%v) want=(%v)", i, err, tt.wantErr)
+		}
+	}
+}
+
+func TestWebhookHandlerDispatch(t *testing.T) {
+	var created, settled []*seedco.Transaction
+	var balances []*seedco.Balance
+	var payments []*seedco.PaymentEvent
+	var others []seedco.EventType
+	wh := &seedco.WebhookHandler{
+		Secret: webhookSecret,
+		OnTransactionCreated: func(ctx context.Context, ev *seedco.TransactionEvent) error {
+			created = append(created, ev.Transaction)
+			return nil
+		},
+		OnTransactionSettled: func(ctx context.Context, ev *seedco.TransactionEvent) error {
+			settled = append(settled, ev.Transaction)
+			return nil
+		},
+		OnBalanceChanged: func(ctx context.Context, ev *seedco.BalanceEvent) error {
+			balances = append(balances, ev.Balance)
+			return nil
+		},
+		OnPaymentStatus: func(ctx context.Context, ev *seedco.PaymentEvent) error {
+			payments = append(payments, ev)
+			return nil
+		},
+		OnEvent: func(ctx context.Context, ev *seedco.Event) error {
+			others = append(others, ev.Type)
+			return nil
+		},
+	}
+
+	deliveries := []struct {
+		typ  seedco.EventType
+		data interface{}
+	}{
+		{seedco.EventTransactionCreated, &seedco.Transaction{ID: "t1", Status: seedco.Pending, AmountCents: -1500}},
+		{seedco.EventTransactionSettled, &seedco.Transaction{ID: "t1", Status: seedco.Settled, AmountCents: -1500}},
+		{seedco.EventBalanceChanged, &seedco.Balance{CheckingAccountID: "a1", Settled: 98500}},
+		{seedco.EventPaymentStatus, map[string]interface{}{"id": "p1", "status": "completed", "previous_status": "processing"}},
+		{"card.issued", map[string]string{"id": "c1"}},
+	}
+	for i, d := range deliveries {
+		req, err := seedcotest.NewWebhookRequest("/webhooks", webhookSecret, d.typ, d.data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec := httptest.NewRecorder()
+		wh.ServeHTTP(rec, req)
+		if g, w := rec.Code, http.StatusOK; g != w {
+			t.Errorf("#%d: %s: status: got=%d want=%d: %s", i, d.typ, g, w, rec.Body)
+		}
+	}
+
+	if len(created) != 1 || created[0].Status != seedco.Pending {
+		t.Errorf("created: got=%+v", created)
+	}
+	if len(settled) != 1 || settled[0].AmountCents != -1500 {
+		t.Errorf("settled: got=%+v", settled)
+	}
+	if len(balances) != 1 || balances[0].Settled != 98500 {
+		t.Errorf("balances: got=%+v", balances)
+	}
+	if len(payments) != 1 || payments[0].Payment.Status != seedco.PaymentCompleted || payments[0].PreviousStatus != seedco.PaymentProcessing {
+		t.Errorf("payments: got=%+v", payments)
+	}
+	if len(others) != 1 || others[0] != "card.issued" {
+		t.Errorf("others: got=%q", others)
+	}
+}
+
+// errAny stands for an error that cannot be compared with errors.Is.
+var errAny = errors.New("any error")
+
+func TestWebhookHandlerRejects(t *testing.T) {
+	errBusy := errors.New("busy")
+	wh := &seedco.WebhookHandler{
+		Secret: webhookSecret,
+		OnTransactionCreated: func(ctx context.Context, ev *seedco.TransactionEvent) error {
+			if ev.Transaction.ID == "busy" {
+				return errBusy
+			}
+			return nil
+		},
+	}
+
+	signed := func(secret []byte, data interface{}) *http.Request {
+		req, err := seedcotest.NewWebhookRequest("/webhooks", secret, seedco.EventTransactionCreated, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return req
+	}
+	staleBody := []byte(`{"id":"ev1","type":"transaction.created","data":{"id":"t1"}}`)
+	stale := httptest.NewRequest("POST", "/webhooks", bytes.NewReader(staleBody))
+	stale.Header.Set(seedco.WebhookSignatureHeader, seedco.SignWebhook(webhookSecret, time.Now().Add(-time.Hour), staleBody))
+
+	var reported error
+	wh.OnError = func(req *http.Request, err error) { reported = err }
+
+	tests := [...]struct {
+		req      *http.Request
+		wantCode int
+		// wantErr is the error passed to OnError,
+		// or errAny for one that is not exported.
+		wantErr error
+	}{
+		0: {req: signed([]byte("whsec_other"), &seedco.Transaction{ID: "t1"}), wantCode: http.StatusUnauthorized, wantErr: seedco.ErrInvalidSignature},
+		1: {req: stale, wantCode: http.StatusUnauthorized, wantErr: seedco.ErrStaleWebhook},
+		2: {req: signed(webhookSecret, &seedco.Transaction{ID: "busy"}), wantCode: http.StatusInternalServerError, wantErr: errBusy},
+		3: {req: signed(webhookSecret, "not a transaction"), wantCode: http.StatusBadRequest, wantErr: errAny},
+		4: {req: httptest.NewRequest("GET", "/webhooks", nil), wantCode: http.StatusMethodNotAllowed},
+	}
+
+	for i, tt := range tests {
+		reported = nil
+		rec := httptest.NewRecorder()
+		wh.ServeHTTP(rec, tt.req)
+		if g, w := rec.Code, tt.wantCode; g != w {
+			t.Errorf("#%d: status: got=%d want=%d", i, g, w)
+		}
+		// Only the status text is sent back, never the error.
+		if g, w := strings.TrimSpace(rec.Body.String()), http.StatusText(tt.wantCode); g != w {
+			t.Errorf("#%d: body: got=%q want=%q", i, g, w)
+		}
+		switch {
+		case tt.wantErr == errAny:
+			if reported == nil {
+				t.Errorf("#%d: OnError: want non-nil error", i)
+			}
+		case !errors.Is(reported, tt.wantErr):
+			t.Errorf("#%d: OnError: got=(%v) want=(%v)", i, reported, tt.wantErr)
+		}
+	}
+}